@@ -0,0 +1,39 @@
+package frontend
+
+import (
+	"bytes"
+	"regexp"
+)
+
+var (
+	baseTagPattern  = regexp.MustCompile(`(?i)<base\s[^>]*>`)
+	headTagPattern  = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+	absoluteURLAttr = regexp.MustCompile(`(?i)(\s(?:src|href|action|poster|data)=["'])/([^/"'][^"']*|)(["'])`)
+)
+
+// rewriteHTML modifies HTML that is built for "/" to work under basePath.
+//
+// It sets <base href> and prefixes absolute URLs in attributes like src="/assets/index.js".
+func rewriteHTML(content []byte, basePath string) []byte {
+	baseTag := []byte(`<base href="` + basePath + `">`)
+	if baseTagPattern.Match(content) {
+		content = baseTagPattern.ReplaceAllLiteral(content, baseTag)
+	} else if loc := headTagPattern.FindIndex(content); loc != nil {
+		result := make([]byte, 0, len(content)+len(baseTag))
+		result = append(result, content[:loc[1]]...)
+		result = append(result, baseTag...)
+		content = append(result, content[loc[1]:]...)
+	}
+	prefix := []byte(basePath[1:])
+	return absoluteURLAttr.ReplaceAllFunc(content, func(m []byte) []byte {
+		sm := absoluteURLAttr.FindSubmatch(m)
+		if bytes.HasPrefix(sm[2], prefix) {
+			return m
+		}
+		result := make([]byte, 0, len(m)+len(prefix))
+		result = append(result, sm[1]...)
+		result = append(result, basePath...)
+		result = append(result, sm[2]...)
+		return append(result, sm[3]...)
+	})
+}
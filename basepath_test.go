@@ -0,0 +1,52 @@
+package frontend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rewriteHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "insert base tag",
+			content: `<html><head><title>app</title></head></html>`,
+			want:    `<html><head><base href="/console/"><title>app</title></head></html>`,
+		},
+		{
+			name:    "replace base tag",
+			content: `<head><base href="/"></head>`,
+			want:    `<head><base href="/console/"></head>`,
+		},
+		{
+			name:    "rewrite absolute asset URLs",
+			content: `<head></head><script src="/assets/index.js"></script><link href='/_next/static/a.css'>`,
+			want:    `<head><base href="/console/"></head><script src="/console/assets/index.js"></script><link href='/console/_next/static/a.css'>`,
+		},
+		{
+			name:    "keep relative, external and prefixed URLs",
+			content: `<head></head><a href="about"></a><img src="//cdn.example.com/a.png"><img src="/console/a.png">`,
+			want:    `<head><base href="/console/"></head><a href="about"></a><img src="//cdn.example.com/a.png"><img src="/console/a.png">`,
+		},
+		{
+			// chunks are loaded by JavaScript, so frontend must be built with the base path
+			name:    "keep module script built with base path",
+			content: `<head></head><script type="module" src="/console/assets/index.js"></script><script type="module">import("/console/assets/chunk.js")</script>`,
+			want:    `<head><base href="/console/"></head><script type="module" src="/console/assets/index.js"></script><script type="module">import("/console/assets/chunk.js")</script>`,
+		},
+		{
+			name:    "don't rewrite import in module script",
+			content: `<head></head><script type="module">import "/assets/chunk.js"</script>`,
+			want:    `<head><base href="/console/"></head><script type="module">import "/assets/chunk.js"</script>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(rewriteHTML([]byte(tt.content), "/console/")))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const (
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	htmlCacheControl      = "no-cache"
+)
+
+// CacheControlFunc returns Cache-Control header value for the file path like "/_next/static/chunks/main.js".
+// If it returns "", the default rule is used.
+type CacheControlFunc func(filePath string) string
+
+// etag returns ETag that is calculated from the file content.
+// embed.FS doesn't have modification time, so content hash is used instead.
+func (s *staticHandler) etag(requestedPath string) (string, error) {
+	if etag, ok := s.etags.Load(requestedPath); ok {
+		return etag.(string), nil
+	}
+	content, err := fs.ReadFile(s.assets, path.Join(s.root, requestedPath))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if !s.live {
+		s.etags.Store(requestedPath, etag)
+	}
+	return etag, nil
+}
+
+// cacheControl returns Cache-Control header value.
+//
+// Files in immutable folders have content hash in their names, so they can be cached forever.
+// HTML should be revalidated every time because it refers the latest assets.
+func (s *staticHandler) cacheControl(requestedPath string) string {
+	if s.opt.CacheControl != nil {
+		if cacheControl := s.opt.CacheControl(requestedPath); cacheControl != "" {
+			return cacheControl
+		}
+	}
+	for _, folder := range s.opt.ImmutableFolders {
+		if strings.HasPrefix(requestedPath, "/"+folder) {
+			return immutableCacheControl
+		}
+	}
+	if path.Ext(requestedPath) == ".html" {
+		return htmlCacheControl
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package frontend
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_staticHandler_Cache(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/build/index.html":                {Data: []byte("root")},
+		"frontend/build/favicon.png":               {Data: []byte("icon")},
+		"frontend/build/_app/immutable/start-a.js": {Data: []byte("js")},
+	}
+	tests := []struct {
+		name             string
+		opt              Opt
+		path             string
+		wantCacheControl string
+	}{
+		{name: "html", path: "/", wantCacheControl: "no-cache"},
+		{name: "fallback html", path: "/users", wantCacheControl: "no-cache"},
+		{name: "immutable", path: "/_app/immutable/start-a.js", wantCacheControl: "public, max-age=31536000, immutable"},
+		{name: "other", path: "/favicon.png", wantCacheControl: ""},
+		{
+			name: "override",
+			opt: Opt{
+				CacheControl: func(filePath string) string {
+					if filePath == "/favicon.png" {
+						return "max-age=3600"
+					}
+					return ""
+				},
+			},
+			path:             "/favicon.png",
+			wantCacheControl: "max-age=3600",
+		},
+		{
+			name:             "override immutable folders",
+			opt:              Opt{ImmutableFolders: []string{}},
+			path:             "/_app/immutable/start-a.js",
+			wantCacheControl: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opt.FrameworkType = SvelteKit
+			h, err := newStaticHandler(assets, normalizeRelOpt(tt.opt))
+			assert.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, tt.wantCacheControl, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func Test_staticHandler_ETag(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/build/index.html": {Data: []byte("root")},
+		"frontend/build/app.js":     {Data: []byte("js")},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: SvelteKit}))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/app.js", nil))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	r := httptest.NewRequest("GET", "/app.js", nil)
+	r.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 304, w.Code)
+	assert.Equal(t, 0, w.Body.Len())
+
+	r = httptest.NewRequest("GET", "/app.js", nil)
+	r.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "js", w.Body.String())
+}
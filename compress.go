@@ -0,0 +1,152 @@
+package frontend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// contentEncoding is precompressed file's encoding and its extension.
+type contentEncoding struct {
+	name string // Content-Encoding value
+	ext  string // sidecar file extension
+}
+
+// contentEncodings is the list of supported encodings in preferred order.
+var contentEncodings = []contentEncoding{
+	{name: "br", ext: ".br"},
+	{name: "zstd", ext: ".zst"},
+	{name: "gzip", ext: ".gz"},
+}
+
+// compressibleExts is the list of file extensions that CompressAssets option compresses.
+var compressibleExts = map[string]bool{
+	".html": true,
+	".js":   true,
+	".mjs":  true,
+	".css":  true,
+	".json": true,
+	".map":  true,
+	".svg":  true,
+	".txt":  true,
+	".xml":  true,
+	".wasm": true,
+}
+
+// minCompressSize is the minimum file size that CompressAssets option compresses.
+const minCompressSize = 1024
+
+// encodedVariant is compressed content of a file.
+type encodedVariant struct {
+	encoding string
+	filePath string // sidecar file path. It is empty when content is compressed in memory
+	content  []byte
+}
+
+// variants returns compressed contents of the file.
+// Sidecar files like "main.js.br" are used first. If there is no sidecar, content compressed at startup is used.
+func (s *staticHandler) variants(requestedPath string) []encodedVariant {
+	// fallback and 404 page are read as "index.html" without leading slash
+	requestedPath = path.Clean("/" + requestedPath)
+	var result []encodedVariant
+	for _, e := range contentEncodings {
+		if s.exists(requestedPath + e.ext) {
+			result = append(result, encodedVariant{
+				encoding: e.name,
+				filePath: requestedPath + e.ext,
+			})
+		}
+	}
+	if len(result) == 0 {
+		if content, ok := s.compressed[requestedPath]; ok {
+			result = append(result, encodedVariant{
+				encoding: "gzip",
+				content:  content,
+			})
+		}
+	}
+	return result
+}
+
+// compressAssets compresses files that don't have sidecar files in memory.
+// Only gzip is available because Go's standard library doesn't have others.
+func (s *staticHandler) compressAssets() error {
+	s.compressed = make(map[string][]byte)
+	return fs.WalkDir(s.assets, s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !compressibleExts[path.Ext(p)] {
+			return err
+		}
+		requestedPath := "/" + p
+		if s.root != "." {
+			requestedPath = "/" + strings.TrimPrefix(p, s.root+"/")
+		}
+		for _, e := range contentEncodings {
+			if s.exists(requestedPath + e.ext) {
+				return nil
+			}
+		}
+		content, err := fs.ReadFile(s.assets, p)
+		if err != nil {
+			return err
+		}
+		if len(content) < minCompressSize {
+			return nil
+		}
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		zw.Write(content)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		if buf.Len() < len(content) {
+			s.compressed[requestedPath] = buf.Bytes()
+		}
+		return nil
+	})
+}
+
+// negotiateEncoding selects the best variant for Accept-Encoding header.
+func negotiateEncoding(r *http.Request, variants []encodedVariant) (encodedVariant, bool) {
+	accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+	var best encodedVariant
+	bestQ := 0.0
+	for _, v := range variants {
+		q, ok := accepted[v.encoding]
+		if !ok {
+			q = accepted["*"]
+		}
+		// variants are in preferred order, so the first one wins when q is same
+		if q > bestQ {
+			best = v
+			bestQ = q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// parseAcceptEncoding returns encodings and their q values.
+func parseAcceptEncoding(header string) map[string]float64 {
+	result := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		result[name] = q
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package frontend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseAcceptEncoding(t *testing.T) {
+	got := parseAcceptEncoding("gzip, br;q=0.5, zstd;q=0, *;q=0.1")
+	assert.Equal(t, map[string]float64{"gzip": 1, "br": 0.5, "zstd": 0, "*": 0.1}, got)
+}
+
+func Test_staticHandler_Precompressed(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/dist/index.html":        {Data: []byte("root")},
+		"frontend/dist/assets/main.js":    {Data: []byte("js")},
+		"frontend/dist/assets/main.js.br": {Data: []byte("js-br")},
+		"frontend/dist/assets/main.js.gz": {Data: []byte("js-gz")},
+	}
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+		wantBody       string
+	}{
+		{name: "no encoding", acceptEncoding: "", wantEncoding: "", wantBody: "js"},
+		{name: "brotli preferred", acceptEncoding: "gzip, deflate, br", wantEncoding: "br", wantBody: "js-br"},
+		{name: "gzip only", acceptEncoding: "gzip", wantEncoding: "gzip", wantBody: "js-gz"},
+		{name: "q value", acceptEncoding: "br;q=0.5, gzip", wantEncoding: "gzip", wantBody: "js-gz"},
+		{name: "not acceptable", acceptEncoding: "br;q=0, deflate", wantEncoding: "", wantBody: "js"},
+		{name: "wildcard", acceptEncoding: "*", wantEncoding: "br", wantBody: "js-br"},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: SolidJS}))
+	assert.NoError(t, err)
+	etags := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/assets/main.js", nil)
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, "text/javascript; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			etags[tt.wantEncoding+":"+w.Header().Get("ETag")] = true
+		})
+	}
+	assert.Len(t, etags, 3)
+}
+
+func Test_staticHandler_CompressAssets(t *testing.T) {
+	large := strings.Repeat("console.log('hello');\n", 100)
+	assets := fstest.MapFS{
+		"frontend/dist/index.html":     {Data: []byte("root")},
+		"frontend/dist/assets/main.js": {Data: []byte(large)},
+		"frontend/dist/200.html":       {Data: []byte(large)},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: SolidJS, CompressAssets: true}))
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/assets/main.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(zr)
+	assert.Equal(t, large, string(content))
+
+	// small file is not compressed
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "root", w.Body.String())
+
+	// fallback page is compressed too
+	h, err = newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: SolidJS, CompressAssets: true, FallbackPath: "200.html"}))
+	assert.NoError(t, err)
+	r = httptest.NewRequest("GET", "/users", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	zr, err = gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(t, err)
+	content, _ = io.ReadAll(zr)
+	assert.Equal(t, large, string(content))
+}
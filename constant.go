@@ -21,26 +21,124 @@ const (
 	NotFound
 )
 
+//go:generate enumer -type=TrailingSlash
+type TrailingSlash int
+
+const (
+	TrailingSlashAsIs   TrailingSlash = iota // Serve both "/about" and "/about/" without redirect
+	TrailingSlashAlways                      // Redirect "/about" to "/about/" if "about/index.html" exists
+	TrailingSlashNever                       // Redirect "/about/" to "/about" if the page exists
+)
+
+//go:generate enumer -type=NotFoundPolicy
+type NotFoundPolicy int
+
+const (
+	NotFoundFallback  NotFoundPolicy = iota // Return fallback page with status 200 for every missing path
+	NotFoundForAssets                       // Return 404 for missing path that has file extension or is under immutable folders
+	NotFoundPage                            // Return framework's 404 page with status 404 for every missing path
+)
+
+//go:generate enumer -type=PackageManager
+type PackageManager int
+
+const (
+	PackageManagerAutoDetect PackageManager = iota // Detect from packageManager field of package.json or lockfile
+	PackageManagerNpm
+	PackageManagerPnpm
+	PackageManagerYarn
+	PackageManagerBun
+)
+
+//go:generate enumer -type=NodeVersionCheck
+type NodeVersionCheck int
+
+const (
+	NodeVersionCheckWarn  NodeVersionCheck = iota // Log warning if node version doesn't match engines.node, .nvmrc or .node-version
+	NodeVersionCheckError                         // Fail to start dev server if node version doesn't match
+	NodeVersionCheckSkip                          // Don't check node version
+)
+
+type packageManagerConfig struct {
+	Name           string   // command name and the name in packageManager field of package.json
+	LockFiles      []string // lockfiles in priority order
+	InstallCommand string   // install command that doesn't update the lockfile
+	InitCommand    string   // install command that is used if there is no lockfile
+}
+
+var packageManagerConfigs = map[PackageManager]packageManagerConfig{
+	PackageManagerNpm: {
+		Name:           "npm",
+		LockFiles:      []string{"package-lock.json"},
+		InstallCommand: "npm ci",
+		InitCommand:    "npm install",
+	},
+	PackageManagerPnpm: {
+		Name:           "pnpm",
+		LockFiles:      []string{"pnpm-lock.yaml"},
+		InstallCommand: "pnpm install --frozen-lockfile",
+		InitCommand:    "pnpm install",
+	},
+	PackageManagerYarn: {
+		Name:           "yarn",
+		LockFiles:      []string{"yarn.lock"},
+		InstallCommand: "yarn install --immutable",
+		InitCommand:    "yarn install",
+	},
+	PackageManagerBun: {
+		Name:           "bun",
+		LockFiles:      []string{"bun.lockb", "bun.lock"},
+		InstallCommand: "bun install",
+		InitCommand:    "bun install",
+	},
+}
+
+// lockFilePriority is the order to detect package manager from lockfiles.
+var lockFilePriority = []PackageManager{PackageManagerPnpm, PackageManagerYarn, PackageManagerBun, PackageManagerNpm}
+
+// runCommand returns the command to run the npm script.
+func (p PackageManager) runCommand(script string) string {
+	c, ok := packageManagerConfigs[p]
+	if !ok {
+		c = packageManagerConfigs[PackageManagerNpm]
+	}
+	return c.Name + " run " + script
+}
+
 type frameworkConfig struct {
 	DistFolder       string
-	DevServerCommand string
+	DevScript        string   // npm script name that runs dev server
+	BasePathArg      string   // dev server's command line option to set base path
+	PortArgs         []string // dev server's command line options to set port. Port number is appended
+	HMRClientArg     string   // dev server's command line option to set "host:port" that HMR client connects to
+	ImmutableFolders []string // folders that contain assets with content hash in their names
 }
 
 var frameworkConfigs = map[FrameworkType]frameworkConfig{
 	NextJS: {
 		DistFolder:       "out",
-		DevServerCommand: "npm run dev",
+		DevScript:        "dev",
+		PortArgs:         []string{"-p"},
+		ImmutableFolders: []string{"_next/static/"},
 	},
 	VueJS: {
 		DistFolder:       "dist",
-		DevServerCommand: "npm run serve",
+		DevScript:        "serve",
+		PortArgs:         []string{"--port"},
+		HMRClientArg:     "--public",
+		ImmutableFolders: []string{"js/", "css/", "img/", "fonts/"},
 	},
 	SvelteKit: {
 		DistFolder:       "build",
-		DevServerCommand: "npm run dev",
+		DevScript:        "dev",
+		PortArgs:         []string{"--strictPort", "--port"},
+		ImmutableFolders: []string{"_app/immutable/"},
 	},
 	SolidJS: {
 		DistFolder:       "dist",
-		DevServerCommand: "npm run dev",
+		DevScript:        "dev",
+		BasePathArg:      "--base",
+		PortArgs:         []string{"--strictPort", "--port"},
+		ImmutableFolders: []string{"assets/"},
 	},
 }
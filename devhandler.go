@@ -0,0 +1,300 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type targetKey struct{}
+
+// devTarget is the destination of development mode proxy.
+type devTarget interface {
+	// Target returns the URL of dev server. It returns nil while dev server is not ready.
+	Target() *url.URL
+	// Failure returns the last failure of dev server.
+	Failure() *devServerFailure
+	// History returns the last lines of dev server's output.
+	History() []string
+}
+
+// fixedTarget is dev server that is run by others.
+type fixedTarget struct {
+	url *url.URL
+}
+
+func (f fixedTarget) Target() *url.URL           { return f.url }
+func (f fixedTarget) Failure() *devServerFailure { return nil }
+func (f fixedTarget) History() []string          { return nil }
+
+// newDevHandler creates handler for development mode.
+//
+// It proxies requests to dev server. The destination is got for each request because
+// the dev server's URL changes when it is restarted. While dev server is not ready,
+// the handler returns waiting page, or error page if dev server failed.
+func newDevHandler(t devTarget, o *Opt) http.Handler {
+	proxy := newProxy(t, o)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := t.Target()
+		if u == nil {
+			if failure := t.Failure(); failure != nil {
+				serveErrorPage(w, r, o, t, failure.Err, failure.Restarting)
+			} else {
+				serveStartingPage(w, r, o)
+			}
+			return
+		}
+		proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), targetKey{}, u)))
+	})
+}
+
+// newProxy creates reverse proxy to dev server. Destination is passed via request's context.
+//
+// HMR websocket is proxied by ReverseProxy's upgrade support, and SSE is flushed immediately.
+func newProxy(t devTarget, o *Opt) *httputil.ReverseProxy {
+	p := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			u := r.In.Context().Value(targetKey{}).(*url.URL)
+			// dev servers reject unknown Host and Origin, so they are rewritten to dev server's
+			r.SetURL(u)
+			if r.In.Header.Get("Origin") != "" {
+				r.Out.Header.Set("Origin", u.Scheme+"://"+u.Host)
+			}
+			r.SetXForwarded()
+			// receive plain text to rewrite URLs in it
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: rewriteDevURLs,
+		FlushInterval:  -1,
+	}
+	// dev server run by supervisor is a local process, and its certificate is usually self-signed
+	if _, local := t.(*supervisor); local || o.DevServerInsecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		p.Transport = transport
+	}
+	if o.ErrorHandler != nil {
+		p.ErrorHandler = o.ErrorHandler
+	} else {
+		// supervisor restarts its dev server, but the one run by others is not managed by frontend-go
+		_, restarting := t.(*supervisor)
+		p.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			serveErrorPage(w, r, o, t, err, restarting)
+		}
+	}
+	return p
+}
+
+// rewriteDevURLs rewrites dev server's absolute URLs in Location header and text response
+// to Go server's URL, so that browser doesn't access dev server directly.
+func rewriteDevURLs(res *http.Response) error {
+	u, ok := res.Request.Context().Value(targetKey{}).(*url.URL)
+	if !ok {
+		return nil
+	}
+	replacer := devURLReplacer(u, res.Request.Header.Get("X-Forwarded-Proto"), res.Request.Header.Get("X-Forwarded-Host"))
+	if replacer == nil {
+		return nil
+	}
+	if location := res.Header.Get("Location"); location != "" {
+		res.Header.Set("Location", replacer.Replace(location))
+	}
+	if !isRewritableContent(res.Header.Get("Content-Type")) || res.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	body = []byte(replacer.Replace(string(body)))
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
+
+// devURLReplacer returns replacer from dev server's origins to Go server's origin.
+// Loopback addresses are treated as the same host.
+func devURLReplacer(u *url.URL, proto, host string) *strings.Replacer {
+	if host == "" || host == u.Host {
+		return nil
+	}
+	if proto == "" {
+		proto = "http"
+	}
+	wsProto := "ws"
+	if proto == "https" {
+		wsProto = "wss"
+	}
+	devWSProto := "ws"
+	if u.Scheme == "https" {
+		devWSProto = "wss"
+	}
+	devHosts := []string{u.Host}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		if u.Port() == "" {
+			break
+		}
+		devHosts = []string{"localhost:" + u.Port(), "127.0.0.1:" + u.Port(), "[::1]:" + u.Port()}
+	}
+	var pairs []string
+	for _, h := range devHosts {
+		pairs = append(pairs,
+			u.Scheme+"://"+h, proto+"://"+host,
+			devWSProto+"://"+h, wsProto+"://"+host,
+			"//"+h, "//"+host)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// isRewritableContent returns true if the content type is text that may contain URLs.
+// Event stream is not buffered.
+func isRewritableContent(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	}
+	return strings.Contains(mediaType, "javascript") || strings.Contains(mediaType, "json") || strings.HasSuffix(mediaType, "xml")
+}
+
+var startingPage = template.Must(template.New("starting").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="1">
+<title>Frontend is starting…</title>
+<style>
+body { font-family: sans-serif; color: #444; display: flex; align-items: center; justify-content: center; height: 100vh; margin: 0; }
+</style>
+</head>
+<body>
+<p>Frontend ({{.}}) is starting…</p>
+</body>
+</html>
+`))
+
+// serveStartingPage returns auto-refreshing page for browsers, and 503 with Retry-After for others.
+func serveStartingPage(w http.ResponseWriter, r *http.Request, o *Opt) {
+	w.Header().Set("Retry-After", "1")
+	w.Header().Set("Cache-Control", "no-store")
+	if !acceptsHTML(r) {
+		http.Error(w, "frontend dev server is starting", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	startingPage.Execute(w, o.FrameworkType.String())
+}
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="2">
+<title>Frontend dev server failed</title>
+<style>
+body { font-family: sans-serif; color: #333; margin: 2em; }
+h1 { color: #c00; font-size: 1.4em; }
+pre { background: #222; color: #eee; padding: 1em; overflow: auto; }
+th { text-align: left; padding-right: 1em; }
+</style>
+</head>
+<body>
+<h1>Frontend dev server failed</h1>
+<p>{{.Error}}</p>
+{{if .Target}}<p>Dev server is not reachable. This page reloads automatically when it starts.</p>{{else if .Restarting}}<p>Restarting… This page reloads automatically when dev server recovers.</p>{{else}}<p>Gave up restarting. Fix the problem and restart the Go server.</p>{{end}}
+<table>
+{{if .Target}}<tr><th>Target</th><td><code>{{.Target}}</code></td></tr>
+{{else}}<tr><th>Command</th><td><code>{{.Command}}</code></td></tr>
+<tr><th>Directory</th><td><code>{{.Dir}}</code></td></tr>
+{{end}}<tr><th>Framework</th><td>{{.Framework}}</td></tr>
+</table>
+{{if .Suggestions}}<h2>Suggestions</h2>
+<ul>{{range .Suggestions}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Output}}<h2>Output</h2>
+<pre>{{range .Output}}{{.}}
+{{end}}</pre>{{end}}
+</body>
+</html>
+`))
+
+// serveErrorPage returns diagnostic page for browsers, and 502 with plain text for others.
+func serveErrorPage(w http.ResponseWriter, r *http.Request, o *Opt, t devTarget, err error, restarting bool) {
+	w.Header().Set("Cache-Control", "no-store")
+	if !acceptsHTML(r) {
+		http.Error(w, "frontend dev server failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	output := t.History()
+	var target string
+	var suggestions []string
+	if f, ok := t.(fixedTarget); ok {
+		// dev server is run by others. frontend-go doesn't know its command and folder
+		target = f.url.String()
+		suggestions = []string{"Start the dev server at " + target + ", or check DevServerURL and Port"}
+	} else {
+		suggestions = suggestFixes(o, err, output)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusBadGateway)
+	errorPage.Execute(w, map[string]any{
+		"Error":       err.Error(),
+		"Restarting":  restarting,
+		"Target":      target,
+		"Command":     o.DevServerCommand,
+		"Dir":         o.FrontEndFolderPath,
+		"Framework":   o.FrameworkType.String(),
+		"Suggestions": suggestions,
+		"Output":      output,
+	})
+}
+
+// suggestFixes guesses how to fix the dev server's failure from error and output.
+func suggestFixes(o *Opt, err error, output []string) []string {
+	var result []string
+	text := err.Error() + "\n" + strings.Join(output, "\n")
+	command, _ := installCommand(o)
+	install := strings.Join(command, " ")
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, ErrNodeNotFound) {
+		result = append(result, "Install Node.js and the package manager, and add them to PATH")
+	}
+	if errors.Is(err, ErrNodeVersionMismatch) {
+		result = append(result, "Switch Node.js version by nvm, fnm, volta and so on, or set NodeVersionCheck to NodeVersionCheckWarn")
+	}
+	if _, statErr := os.Stat(filepath.Join(o.FrontEndFolderPath, "node_modules")); os.IsNotExist(statErr) {
+		result = append(result, "node_modules is not found. Run \""+install+"\" in "+o.FrontEndFolderPath)
+	} else if strings.Contains(text, "Cannot find module") || strings.Contains(text, "Cannot find package") || strings.Contains(text, "command not found") {
+		result = append(result, "Some dependencies are missing. Run \""+install+"\" in "+o.FrontEndFolderPath)
+	}
+	if strings.Contains(text, "EADDRINUSE") || strings.Contains(text, "address already in use") {
+		result = append(result, "The port is already in use. Stop other dev server or specify another port")
+	}
+	if strings.Contains(text, "SyntaxError") {
+		result = append(result, "Check syntax error in config files like vite.config.*, next.config.js, svelte.config.js or vue.config.js")
+	}
+	if errors.Is(err, ErrDevServerNotReady) {
+		result = append(result, "Dev server didn't print its URL. Check DevServerCommand or increase DevServerTimeout")
+	}
+	return result
+}
+
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
@@ -0,0 +1,238 @@
+package frontend
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_devHandler(t *testing.T) {
+	devServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("dev server: " + r.URL.Path))
+	}))
+	defer devServer.Close()
+
+	target := &fakeTarget{}
+	h := newDevHandler(target, &Opt{FrameworkType: VueJS, DevServerCommand: "npm run serve", FrontEndFolderPath: t.TempDir()})
+
+	t.Run("starting page for browser", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", "text/html,application/xhtml+xml")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `http-equiv="refresh"`)
+	})
+	t.Run("503 for others", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/main.js", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.NotContains(t, w.Body.String(), "<html>")
+	})
+	t.Run("error page", func(t *testing.T) {
+		target.failure = &devServerFailure{Err: ErrDevServerExited, Restarting: true}
+		target.history = []string{"Error: Cannot find module '@vue/cli-service'"}
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "npm run serve")
+		assert.Contains(t, body, "VueJS")
+		assert.Contains(t, body, "Cannot find module &#39;@vue/cli-service&#39;")
+		assert.Contains(t, body, "node_modules is not found")
+		assert.Contains(t, body, `http-equiv="refresh"`)
+	})
+	t.Run("proxy after ready", func(t *testing.T) {
+		target.url, _ = url.Parse(devServer.URL)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/main.js", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "dev server: /main.js", w.Body.String())
+	})
+}
+
+func Test_devHandler_FixedTarget(t *testing.T) {
+	// closed port
+	devServer := httptest.NewServer(http.NotFoundHandler())
+	devServer.Close()
+	u, _ := url.Parse(devServer.URL)
+	h := newDevHandler(fixedTarget{url: u}, &Opt{FrameworkType: VueJS, DevServerCommand: "npm run serve", FrontEndFolderPath: t.TempDir()})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, devServer.URL)
+	assert.Contains(t, body, "Start the dev server at")
+	assert.NotContains(t, body, "Restarting")
+	assert.NotContains(t, body, "npm run serve")
+	assert.NotContains(t, body, "node_modules")
+	assert.Contains(t, body, `http-equiv="refresh"`)
+}
+
+func Test_devHandler_HMR(t *testing.T) {
+	release := make(chan struct{})
+	devServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws":
+			// minimum websocket server that echoes after upgrade
+			conn, rw, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+			rw.Flush()
+			line, _ := rw.ReadString('\n')
+			rw.WriteString("echo: " + line)
+			rw.Flush()
+		case "/@vite/client":
+			// HMR client connects to the dev server's port if it isn't configured
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(`const socket = new WebSocket("ws://` + r.Host + `/ws")`))
+		case "/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: update\n\n"))
+			http.NewResponseController(w).Flush()
+			<-release
+		}
+	}))
+	defer devServer.Close()
+	defer close(release)
+
+	target := &fakeTarget{}
+	target.url, _ = url.Parse(devServer.URL)
+	front := httptest.NewServer(newDevHandler(target, &Opt{FrameworkType: VueJS}))
+	defer front.Close()
+
+	t.Run("websocket", func(t *testing.T) {
+		conn, err := net.Dial("tcp", front.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+		r := bufio.NewReader(conn)
+		res, err := http.ReadResponse(r, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+		conn.Write([]byte("hello\n"))
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hello\n", line)
+	})
+	t.Run("HMR client connects via Go port", func(t *testing.T) {
+		res, err := http.Get(front.URL + "/@vite/client")
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		m := regexp.MustCompile(`ws://([^/"]+)(/[^"]*)`).FindStringSubmatch(string(body))
+		if !assert.NotNil(t, m) {
+			return
+		}
+		assert.Equal(t, front.Listener.Addr().String(), m[1])
+
+		conn, err := net.Dial("tcp", m[1])
+		assert.NoError(t, err)
+		defer conn.Close()
+		conn.Write([]byte("GET " + m[2] + " HTTP/1.1\r\nHost: " + m[1] + "\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+		r := bufio.NewReader(conn)
+		upgrade, err := http.ReadResponse(r, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, upgrade.StatusCode)
+		conn.Write([]byte("update\n"))
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: update\n", line)
+	})
+	t.Run("server-sent events", func(t *testing.T) {
+		res, err := http.Get(front.URL + "/events")
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		buf := make([]byte, len("data: update\n\n"))
+		// dev server is still streaming, so it is read only if the proxy flushes
+		_, err = io.ReadFull(res.Body, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "data: update\n\n", string(buf))
+	})
+}
+
+func Test_devHandler_rewrite(t *testing.T) {
+	devServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := "http://" + r.Host
+		switch r.URL.Path {
+		case "/headers":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte(r.Host + " " + r.Header.Get("Origin") + " " + r.Header.Get("X-Forwarded-Host")))
+		case "/index.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<script src="` + origin + `/main.js"></script>`))
+		case "/main.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(`new WebSocket("ws://` + r.Host + `/")`))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte(origin))
+		case "/redirect":
+			http.Redirect(w, r, origin+"/index.html", http.StatusFound)
+		}
+	}))
+	defer devServer.Close()
+
+	target := &fakeTarget{}
+	target.url, _ = url.Parse(devServer.URL)
+	h := newDevHandler(target, &Opt{FrameworkType: VueJS})
+	get := func(p string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "http://localhost:8888"+p, nil)
+		r.Header.Set("Origin", "http://localhost:8888")
+		r.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	devHost := target.url.Host
+
+	assert.Equal(t, devHost+" http://"+devHost+" localhost:8888", get("/headers").Body.String())
+	assert.Equal(t, `<script src="http://localhost:8888/main.js"></script>`, get("/index.html").Body.String())
+	assert.Equal(t, `new WebSocket("ws://localhost:8888/")`, get("/main.js").Body.String())
+	assert.Equal(t, "http://"+devHost, get("/image.png").Body.String())
+	assert.Equal(t, "http://localhost:8888/index.html", get("/redirect").Header().Get("Location"))
+}
+
+func Test_devURLReplacer(t *testing.T) {
+	u, _ := url.Parse("http://localhost:5173")
+	r := devURLReplacer(u, "https", "example.com")
+	assert.Equal(t,
+		"https://example.com/a wss://example.com/b //example.com/c https://example.com/d",
+		r.Replace("http://localhost:5173/a ws://127.0.0.1:5173/b //localhost:5173/c http://[::1]:5173/d"))
+	assert.Nil(t, devURLReplacer(u, "http", "localhost:5173"))
+}
+
+func Test_devServerEnv(t *testing.T) {
+	assert.Nil(t, devServerEnv(&Opt{}))
+	assert.Equal(t, []string{
+		"FRONTEND_GO_BASE_PATH=/console/",
+		"FRONTEND_GO_HMR_CLIENT_PORT=8080",
+	}, devServerEnv(&Opt{BasePath: "/console/", HMRClientPort: 8080}))
+}
+
+type fakeTarget struct {
+	url     *url.URL
+	failure *devServerFailure
+	history []string
+}
+
+func (f *fakeTarget) Target() *url.URL           { return f.url }
+func (f *fakeTarget) Failure() *devServerFailure { return f.failure }
+func (f *fakeTarget) History() []string          { return f.history }
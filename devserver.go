@@ -3,10 +3,21 @@ package frontend
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/shlex"
 )
@@ -27,47 +38,333 @@ func parseCmd(cmdStr string) (cmd string, args []string, err error) {
 	return
 }
 
+// appendScriptArgs appends extra arguments to the dev server command.
+// npm needs "--" to pass arguments to the script.
+func appendScriptArgs(cmdName string, args, extra []string) []string {
+	if len(extra) == 0 {
+		return args
+	}
+	if cmdName == "npm" {
+		hasSeparator := false
+		for _, a := range args {
+			if a == "--" {
+				hasSeparator = true
+			}
+		}
+		if !hasSeparator {
+			args = append(args, "--")
+		}
+	}
+	return append(args, extra...)
+}
+
+// devServerArgs returns dev server's command and arguments.
+//
+// The framework's default command accepts port option. Otherwise knownCommand is false,
+// and only PORT env var is available to pass the port.
+func devServerArgs(o *Opt, port int) (cmdName string, args []string, knownCommand bool, err error) {
+	cmdName, args, err = parseCmd(o.DevServerCommand)
+	if err != nil {
+		return "", nil, false, err
+	}
+	c, ok := frameworkConfigs[o.FrameworkType]
+	knownCommand = ok && o.DevServerCommand == o.PackageManager.runCommand(c.DevScript) && len(c.PortArgs) > 0
+	var extra []string
+	if knownCommand {
+		extra = append(extra, c.PortArgs...)
+		extra = append(extra, strconv.Itoa(port))
+	}
+	if o.BasePath != "" && c.BasePathArg != "" {
+		extra = append(extra, c.BasePathArg, o.BasePath)
+	}
+	if o.HMRClientPort != 0 && c.HMRClientArg != "" {
+		// "0.0.0.0" means the host of the page
+		extra = append(extra, c.HMRClientArg, "0.0.0.0:"+strconv.Itoa(int(o.HMRClientPort)))
+	}
+	return cmdName, appendScriptArgs(cmdName, args, extra), knownCommand, nil
+}
+
+// devServerEnv returns environment variables that tell frontend-go's settings to dev server.
+// Framework's config file can refer them.
+func devServerEnv(o *Opt) []string {
+	var env []string
+	if o.BasePath != "" {
+		env = append(env, "FRONTEND_GO_BASE_PATH="+o.BasePath)
+	}
+	if o.HMRClientPort != 0 {
+		env = append(env, "FRONTEND_GO_HMR_CLIENT_PORT="+strconv.Itoa(int(o.HMRClientPort)))
+	}
+	return env
+}
+
+var (
+	ErrDevServerNotReady = errors.New("dev server is not ready")
+	ErrDevServerExited   = errors.New("dev server exited")
+)
+
+// defaultDevServerTimeout is the default value of [Opt].DevServerTimeout.
+const defaultDevServerTimeout = time.Minute
+
+var (
+	ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	devServerURL      = regexp.MustCompile(`https?://(?:\[[0-9A-Fa-f:.]+\]|[0-9A-Za-z.-]+):\d+`)
+)
+
+// findDevServerURL finds dev server URL from a line of its output.
+// Dev servers print URL with colors, so ANSI escape sequences are removed before matching.
+func findDevServerURL(line string) (string, bool) {
+	m := devServerURL.FindString(stripANSI(line))
+	if m == "" {
+		return "", false
+	}
+	// "0.0.0.0" and "[::]" mean all interfaces. They are not valid as destination.
+	m = strings.Replace(m, "//0.0.0.0:", "//localhost:", 1)
+	m = strings.Replace(m, "//[::]:", "//localhost:", 1)
+	return m, true
+}
+
+func stripANSI(line string) string {
+	return ansiEscapePattern.ReplaceAllString(line, "")
+}
+
+// lineBuffer keeps the last lines of output. It is safe for concurrent use.
+type lineBuffer struct {
+	lock  sync.Mutex
+	max   int
+	lines []string
+}
+
+func newLineBuffer(max int) *lineBuffer {
+	return &lineBuffer{max: max}
+}
+
+func (b *lineBuffer) Add(line string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.max {
+		b.lines = b.lines[len(b.lines)-b.max:]
+	}
+}
+
+func (b *lineBuffer) Lines() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	result := make([]string, len(b.lines))
+	copy(result, b.lines)
+	return result
+}
+
+// maxStderrLines is the number of stderr lines that is shown in error message.
+const maxStderrLines = 20
+
+// shutdownGracePeriod is the time to wait after SIGTERM before SIGKILL.
+const shutdownGracePeriod = 5 * time.Second
+
 type devServer struct {
-	ctx    context.Context
-	cancel context.CancelFunc
+	ctx     context.Context
+	cancel  context.CancelFunc
+	process *os.Process
+	exited  chan struct{} // closed when the process exits
+	err     error         // result of cmd.Wait(). Available after exited is closed
+	stderr  *lineBuffer
+	keep    bool // if true, Stop() doesn't stop the process. See [Opt].KeepDevServer
+
+	// stopOnce makes concurrent kill() calls signal once and all wait until the process group exits
+	stopOnce sync.Once
 }
 
-func startDevServer(ctx context.Context, folder, cmdStr string) (d *devServer, host string, err error) {
+// startDevServer runs dev server and waits until it gets ready.
+//
+// It passes [Opt].Port or a free port to dev server by command line option and PORT env var,
+// and polls the port. For custom commands, the URL printed on stdout is used too.
+//
+// If history is not nil, dev server's output is recorded to it.
+//
+// It returns error that wraps [ErrDevServerExited] if the process exits before that,
+// and [ErrDevServerNotReady] if it doesn't print URL within [Opt].DevServerTimeout.
+func startDevServer(ctx context.Context, o *Opt, history *lineBuffer) (d *devServer, host string, err error) {
+	if o.KeepDevServer {
+		if d, host, ok := attachDevServer(ctx, o, history); ok {
+			return d, host, nil
+		}
+	}
+	if err := checkNodeVersion(o); err != nil {
+		return nil, "", err
+	}
+	if !o.SkipInstall {
+		if err := installDependencies(ctx, o, history); err != nil {
+			return nil, "", err
+		}
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	d = &devServer{
 		ctx:    ctx,
 		cancel: cancel,
+		exited: make(chan struct{}),
+		stderr: newLineBuffer(maxStderrLines),
 	}
 
-	cmdName, args, err := parseCmd(cmdStr)
+	port := int(o.Port)
+	if port == 0 {
+		port, err = freePort()
+		if err != nil {
+			cancel()
+			return nil, "", err
+		}
+	}
+	cmdName, args, knownCommand, err := devServerArgs(o, port)
 	if err != nil {
+		cancel()
 		return nil, "", err
 	}
 
-	cmd := exec.CommandContext(ctx, cmdName, args...)
-	cmd.Dir = folder
-	stdout, _ := cmd.StdoutPipe()
-	ch := make(chan string)
+	cmd := exec.Command(cmdName, args...)
+	setProcessGroup(cmd)
+	if !o.KeepDevServer {
+		stopWithParent(cmd)
+	}
+	cmd.Dir = o.FrontEndFolderPath
+	cmd.Env = append(os.Environ(), devServerEnv(o)...)
+	cmd.Env = append(cmd.Env, "PORT="+strconv.Itoa(port))
+	var stdout, stderr io.ReadCloser
+	var writers []*os.File
+	if o.KeepDevServer {
+		// dev server keeps running after Go server exits. Its output is written to log file
+		// instead of pipes, and the log file is tailed while Go server is running.
+		logPath := filepath.Join(o.FrontEndFolderPath, devServerLogFile)
+		logW, err := os.Create(logPath)
+		if err != nil {
+			cancel()
+			return nil, "", err
+		}
+		logR, err := os.Open(logPath)
+		if err != nil {
+			logW.Close()
+			cancel()
+			return nil, "", err
+		}
+		stdout = &tailReader{ctx: ctx, file: logR}
+		cmd.Stdout = logW
+		cmd.Stderr = logW
+		writers = []*os.File{logW}
+	} else {
+		// os.Pipe is used instead of cmd.StdoutPipe() to read all output even after the process exits.
+		stdoutR, stdoutW, err := os.Pipe()
+		if err != nil {
+			cancel()
+			return nil, "", err
+		}
+		stderrR, stderrW, err := os.Pipe()
+		if err != nil {
+			cancel()
+			return nil, "", err
+		}
+		stdout, stderr = stdoutR, stderrR
+		cmd.Stdout = stdoutW
+		cmd.Stderr = stderrW
+		writers = []*os.File{stdoutW, stderrW}
+	}
+	output := newOutputForwarder(o)
+	output.history = history
+	ch := make(chan string, 1)
+	outputDone := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
 	go func() {
-		re := regexp.MustCompile(`(http://[0-9A-Za-z.]+:\d+)`)
+		defer wg.Done()
+		defer stdout.Close()
+		// The printed URL is used with port polling. It tells the scheme if dev server uses https.
+		// For known command, only the URL of the passed port is accepted.
 		foundPort := false
 		scanner := bufio.NewScanner(stdout)
 		for scanner.Scan() {
 			if !foundPort {
-				m := re.FindStringSubmatch(scanner.Text())
-				if len(m) > 0 {
-					ch <- m[0]
+				if u, ok := findDevServerURL(scanner.Text()); ok && (!knownCommand || urlPort(u) == port) {
+					notify(ch, u)
 					foundPort = true
 				}
 			}
-			fmt.Println(scanner.Text())
+			if stderr == nil {
+				// log file has both stdout and stderr
+				d.stderr.Add(scanner.Text())
+			}
+			output.Forward("stdout", scanner.Text())
 		}
 	}()
+	if stderr != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer stderr.Close()
+			scanner := bufio.NewScanner(stderr)
+			for scanner.Scan() {
+				d.stderr.Add(scanner.Text())
+				output.Forward("stderr", scanner.Text())
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outputDone)
+	}()
 	err = cmd.Start()
+	for _, w := range writers {
+		w.Close()
+	}
 	if err != nil {
-		return
+		cancel()
+		return nil, "", err
+	}
+	d.process = cmd.Process
+	d.keep = o.KeepDevServer
+	go func() {
+		d.err = cmd.Wait()
+		close(d.exited)
+	}()
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	defer stopPolling()
+	go pollPort(pollCtx, port, ch)
+
+	timeout := o.DevServerTimeout
+	if timeout == 0 {
+		timeout = defaultDevServerTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case host = <-ch:
+	case <-d.exited:
+		d.kill()
+		// wait for the rest of output for error message
+		select {
+		case <-outputDone:
+		case <-time.After(time.Second):
+		}
+		return nil, "", fmt.Errorf("%w: '%s' in '%s' exited with code %d before getting ready: %s",
+			ErrDevServerExited, o.DevServerCommand, o.FrontEndFolderPath, cmd.ProcessState.ExitCode(), strings.Join(d.stderr.Lines(), "\n"))
+	case <-timer.C:
+		d.kill()
+		return nil, "", fmt.Errorf("%w: '%s' in '%s' didn't get ready within %s: %s",
+			ErrDevServerNotReady, o.DevServerCommand, o.FrontEndFolderPath, timeout, strings.Join(d.stderr.Lines(), "\n"))
+	case <-ctx.Done():
+		d.kill()
+		return nil, "", ctx.Err()
+	}
+	if o.KeepDevServer {
+		err = writeDevServerState(o.FrontEndFolderPath, &devServerState{
+			PID:           d.process.Pid,
+			URL:           host,
+			Command:       o.DevServerCommand,
+			Port:          uint16(port),
+			BasePath:      o.BasePath,
+			HMRClientPort: o.HMRClientPort,
+		})
+		if err != nil {
+			d.kill()
+			return nil, "", err
+		}
 	}
-	host = <-ch
 	go func() {
 		<-ctx.Done()
 		d.Stop()
@@ -75,15 +372,115 @@ func startDevServer(ctx context.Context, folder, cmdStr string) (d *devServer, h
 	return
 }
 
+// freePort returns a port number that is not used now.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// urlPort returns the port number of the URL, or 0 if it doesn't have port.
+func urlPort(u string) int {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(parsed.Port())
+	return port
+}
+
+// pollPort sends the URL to ch when the port responds to HTTP or HTTPS request.
+func pollPort(ctx context.Context, port int, ch chan<- string) {
+	// HTTPS server may respond to HTTP request with error page, so HTTPS is tried first
+	urls := []string{"https://localhost:" + strconv.Itoa(port), "http://localhost:" + strconv.Itoa(port)}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// dev server's certificate is usually self-signed
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, u := range urls {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+			res, err := client.Do(req)
+			if err == nil {
+				res.Body.Close()
+				notify(ch, u)
+				return
+			}
+		}
+	}
+}
+
+// notify sends the URL if no one has sent yet.
+func notify(ch chan<- string, u string) {
+	select {
+	case ch <- u:
+	default:
+	}
+}
+
+// Stop stops dev server and its child processes, and waits until they exit.
+// If [Opt].KeepDevServer is true, it only stops watching the process.
 func (d *devServer) Stop() {
-	if d.cancel == nil {
+	if d.keep {
+		d.cancel()
 		return
 	}
-	d.cancel()
-	d.cancel = nil
-	d.Wait()
+	d.kill()
+}
+
+// kill stops dev server and its child processes, and waits until they exit.
+//
+// It sends SIGTERM to the process group first, and SIGKILL if they are still alive after grace period.
+// It is safe to call it several times. Every call returns after the processes exit.
+func (d *devServer) kill() {
+	d.stopOnce.Do(func() {
+		d.cancel()
+		terminateProcessGroup(d.process)
+		if !d.waitGroupExit(shutdownGracePeriod) {
+			killProcessGroup(d.process)
+			d.waitGroupExit(shutdownGracePeriod)
+		}
+	})
+}
+
+// waitGroupExit waits until all processes in the process group exit.
+func (d *devServer) waitGroupExit(timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	select {
+	case <-d.exited:
+	case <-deadline:
+		return false
+	}
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for processGroupAlive(d.process) {
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
 }
 
+// Wait waits until the process exits.
 func (d *devServer) Wait() {
-	<-d.ctx.Done()
+	<-d.exited
 }
@@ -0,0 +1,19 @@
+package frontend
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// stopWithParent asks kernel to send SIGTERM to the command when Go server dies without
+// stopping it (panic, SIGKILL and so on). Call it after [setProcessGroup].
+//
+// The signal is sent to the direct child (npm, pnpm and so on) only. Package managers
+// forward it to the dev server. Note that it is bound to the OS thread that starts
+// the command, but Go runtime doesn't terminate threads unless they are locked.
+func stopWithParent(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Pdeathsig = syscall.SIGTERM
+}
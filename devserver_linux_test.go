@@ -0,0 +1,55 @@
+package frontend
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_startDevServer_ParentDeath runs startDevServer in a child test process,
+// kills it by SIGKILL and checks that the dev server doesn't become an orphan.
+func Test_startDevServer_ParentDeath(t *testing.T) {
+	if folder := os.Getenv("FRONTEND_GO_PARENT_DEATH_TEST"); folder != "" {
+		_, _, err := startDevServer(context.Background(), &Opt{
+			FrontEndFolderPath: folder,
+			DevServerCommand:   `sh -c "echo $$ > pid; echo 'Local: http://localhost:5173/'; exec sleep 60"`,
+			DevServerOutput:    &strings.Builder{},
+			SkipInstall:        true,
+		}, nil)
+		if err == nil {
+			time.Sleep(time.Minute)
+		}
+		return
+	}
+
+	folder := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_startDevServer_ParentDeath$")
+	cmd.Env = append(os.Environ(), "FRONTEND_GO_PARENT_DEATH_TEST="+folder)
+	assert.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	var pid int
+	assert.Eventually(t, func() bool {
+		b, err := os.ReadFile(filepath.Join(folder, "pid"))
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(b)))
+		return err == nil && pid != 0
+	}, 10*time.Second, 10*time.Millisecond)
+	if pid == 0 {
+		return
+	}
+	defer syscall.Kill(-pid, syscall.SIGKILL)
+
+	cmd.Process.Kill()
+	cmd.Wait()
+	assert.Eventually(t, func() bool {
+		return !processAlive(pid)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package frontend
+
+import "os/exec"
+
+// stopWithParent does nothing because only Linux can stop child process when parent dies.
+// Cancel the context passed to the handler or call Stop() before Go server exits.
+func stopWithParent(cmd *exec.Cmd) {}
@@ -0,0 +1,228 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findDevServerURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   string
+		wantOk bool
+	}{
+		{name: "vite", line: "  ➜  Local:   http://localhost:5173/", want: "http://localhost:5173", wantOk: true},
+		{name: "ansi color", line: "  \x1b[32m➜\x1b[39m  \x1b[1mLocal\x1b[22m:   \x1b[36mhttp://localhost:\x1b[1m5173\x1b[22m/\x1b[39m", want: "http://localhost:5173", wantOk: true},
+		{name: "https", line: "ready - started server on https://127.0.0.1:3000", want: "https://127.0.0.1:3000", wantOk: true},
+		{name: "ipv6", line: "Local: http://[::1]:5173/", want: "http://[::1]:5173", wantOk: true},
+		{name: "all interfaces", line: "ready - started server on 0.0.0.0:3000, url: http://0.0.0.0:3000", want: "http://localhost:3000", wantOk: true},
+		{name: "no url", line: "compiling...", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findDevServerURL(tt.line)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_startDevServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		timeout  time.Duration
+		wantHost string
+		wantErr  error
+		wantMsg  string
+	}{
+		{
+			name:     "ready",
+			command:  `sh -c "echo 'Local: http://localhost:5173/'; sleep 10"`,
+			wantHost: "http://localhost:5173",
+		},
+		{
+			name:    "exited",
+			command: `sh -c "echo 'Cannot find module vite' >&2; exit 3"`,
+			wantErr: ErrDevServerExited,
+			wantMsg: "Cannot find module vite",
+		},
+		{
+			name:    "timeout",
+			command: `sh -c "echo 'starting' >&2; sleep 10"`,
+			timeout: 100 * time.Millisecond,
+			wantErr: ErrDevServerNotReady,
+			wantMsg: "starting",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, host, err := startDevServer(context.Background(), &Opt{
+				FrontEndFolderPath: t.TempDir(),
+				DevServerCommand:   tt.command,
+				DevServerTimeout:   tt.timeout,
+			}, nil)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				assert.Contains(t, err.Error(), tt.wantMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantHost, host)
+				d.Stop()
+			}
+		})
+	}
+}
+
+func Test_devServer_StopProcessTree(t *testing.T) {
+	d, _, err := startDevServer(context.Background(), &Opt{
+		FrontEndFolderPath: t.TempDir(),
+		// grandchild process keeps running if only sh is killed
+		DevServerCommand: `sh -c "sleep 30 & echo 'http://localhost:5173'; wait"`,
+	}, nil)
+	assert.NoError(t, err)
+	assert.True(t, processGroupAlive(d.process))
+	d.Stop()
+	assert.False(t, processGroupAlive(d.process))
+}
+
+func Test_devServerArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		opt           Opt
+		wantCmd       string
+		wantArgs      []string
+		wantKnownPort bool
+	}{
+		{
+			name:          "Vite",
+			opt:           Opt{FrameworkType: SolidJS, DevServerCommand: "npm run dev"},
+			wantCmd:       "npm",
+			wantArgs:      []string{"run", "dev", "--", "--strictPort", "--port", "5000"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "Vite with base path",
+			opt:           Opt{FrameworkType: SolidJS, DevServerCommand: "npm run dev", BasePath: "/app/"},
+			wantCmd:       "npm",
+			wantArgs:      []string{"run", "dev", "--", "--strictPort", "--port", "5000", "--base", "/app/"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "Next.js",
+			opt:           Opt{FrameworkType: NextJS, DevServerCommand: "npm run dev"},
+			wantCmd:       "npm",
+			wantArgs:      []string{"run", "dev", "--", "-p", "5000"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "vue-cli",
+			opt:           Opt{FrameworkType: VueJS, DevServerCommand: "npm run serve"},
+			wantCmd:       "npm",
+			wantArgs:      []string{"run", "serve", "--", "--port", "5000"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "vue-cli with HMR client port",
+			opt:           Opt{FrameworkType: VueJS, DevServerCommand: "npm run serve", HMRClientPort: 8080},
+			wantCmd:       "npm",
+			wantArgs:      []string{"run", "serve", "--", "--port", "5000", "--public", "0.0.0.0:8080"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "pnpm",
+			opt:           Opt{FrameworkType: SvelteKit, PackageManager: PackageManagerPnpm, DevServerCommand: "pnpm run dev"},
+			wantCmd:       "pnpm",
+			wantArgs:      []string{"run", "dev", "--strictPort", "--port", "5000"},
+			wantKnownPort: true,
+		},
+		{
+			name:          "custom command",
+			opt:           Opt{FrameworkType: NextJS, DevServerCommand: "yarn start"},
+			wantCmd:       "yarn",
+			wantArgs:      []string{"start"},
+			wantKnownPort: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, known, err := devServerArgs(&tt.opt, 5000)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCmd, cmd)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantKnownPort, known)
+		})
+	}
+}
+
+func Test_startDevServer_Port(t *testing.T) {
+	// PORT env var is passed to custom command, and readiness is detected by polling the port
+	l, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	server := &http.Server{Handler: http.NotFoundHandler()}
+	go server.Serve(l)
+	defer server.Close()
+
+	d, host, err := startDevServer(context.Background(), &Opt{
+		FrontEndFolderPath: t.TempDir(),
+		DevServerCommand:   `sh -c "echo port=$PORT; sleep 10"`,
+		Port:               uint16(port),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:"+strconv.Itoa(port), host)
+	d.Stop()
+}
+
+func Test_startDevServer_HTTPS(t *testing.T) {
+	t.Run("printed https URL of known command", func(t *testing.T) {
+		// Vite's default command prints https URL with the passed port (the last argument)
+		fakeCommand(t, "npm", `for a; do p=$a; done; echo "  Local: https://localhost:$p/"; sleep 10`)
+		o := &Opt{
+			FrameworkType:      SolidJS,
+			PackageManager:     PackageManagerNpm,
+			DevServerCommand:   "npm run dev",
+			FrontEndFolderPath: t.TempDir(),
+			Port:               uint16(mustFreePort(t)),
+		}
+		d, host, err := startDevServer(context.Background(), o, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://localhost:"+strconv.Itoa(int(o.Port)), host)
+		d.Stop()
+	})
+	t.Run("polling https port", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.NotFoundHandler())
+		l, err := net.Listen("tcp", "localhost:0")
+		assert.NoError(t, err)
+		server.Listener = l
+		server.StartTLS()
+		defer server.Close()
+		port := l.Addr().(*net.TCPAddr).Port
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		ch := make(chan string, 1)
+		go pollPort(ctx, port, ch)
+		select {
+		case u := <-ch:
+			assert.Equal(t, "https://localhost:"+strconv.Itoa(port), u)
+		case <-ctx.Done():
+			t.Error("https port is not detected")
+		}
+	})
+}
+
+func mustFreePort(t *testing.T) int {
+	port, err := freePort()
+	assert.NoError(t, err)
+	return port
+}
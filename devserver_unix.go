@@ -0,0 +1,31 @@
+//go:build !windows
+
+package frontend
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs the command in its own process group
+// to stop grandchild processes like node run by npm together.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func terminateProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+}
+
+func killProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
+
+func processGroupAlive(p *os.Process) bool {
+	return processAlive(p.Pid)
+}
+
+func processAlive(pid int) bool {
+	return syscall.Kill(-pid, 0) == nil
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package frontend
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup runs the command in its own process group
+// to stop grandchild processes like node run by npm together.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func terminateProcessGroup(p *os.Process) error {
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(p.Pid)).Run()
+}
+
+func killProcessGroup(p *os.Process) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid)).Run()
+}
+
+// processGroupAlive always returns false because Windows doesn't have process group ID to check.
+// taskkill /T stops the process tree instead.
+func processGroupAlive(p *os.Process) bool {
+	return false
+}
+
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,193 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// devServerStateFile is put in frontend folder to reuse dev server. See [Opt].KeepDevServer.
+	devServerStateFile = ".frontend-go-devserver.json"
+	// devServerLogFile is put in frontend folder to keep dev server's output.
+	devServerLogFile = ".frontend-go-devserver.log"
+)
+
+var ErrDevServerNotRunning = errors.New("kept dev server is not running")
+
+// devServerState is the kept dev server and the options that it was started with.
+type devServerState struct {
+	PID           int    `json:"pid"`
+	URL           string `json:"url"`
+	Command       string `json:"command"`
+	Port          uint16 `json:"port"`
+	BasePath      string `json:"basePath"`
+	HMRClientPort uint16 `json:"hmrClientPort"`
+}
+
+// matches returns true if the dev server was started with the same options.
+// Any port is accepted if [Opt].Port is not specified.
+func (s *devServerState) matches(o *Opt) bool {
+	return s.Command == o.DevServerCommand &&
+		(o.Port == 0 || s.Port == o.Port) &&
+		s.BasePath == o.BasePath &&
+		s.HMRClientPort == o.HMRClientPort
+}
+
+func readDevServerState(folder string) (*devServerState, error) {
+	content, err := os.ReadFile(filepath.Join(folder, devServerStateFile))
+	if err != nil {
+		return nil, err
+	}
+	var s devServerState
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func writeDevServerState(folder string, s *devServerState) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(folder, devServerStateFile), content, 0o644)
+}
+
+func removeDevServerState(folder string) {
+	os.Remove(filepath.Join(folder, devServerStateFile))
+}
+
+// attachDevServer reuses dev server that was run by previous Go server process.
+// It returns false if there is no state file, or the process or URL is not alive.
+// If the dev server was started with different options, it is stopped to start new one.
+func attachDevServer(ctx context.Context, o *Opt, history *lineBuffer) (*devServer, string, bool) {
+	state, err := readDevServerState(o.FrontEndFolderPath)
+	if err != nil {
+		return nil, "", false
+	}
+	if !processAlive(state.PID) || !urlAlive(state.URL) {
+		removeDevServerState(o.FrontEndFolderPath)
+		return nil, "", false
+	}
+	if !state.matches(o) {
+		logEvent(o, slog.LevelInfo, "stop running dev server because options are changed", "pid", state.PID, "url", state.URL)
+		if err := stopKeptDevServer(state.PID); err != nil {
+			logEvent(o, slog.LevelWarn, "can't stop running dev server", "pid", state.PID, "error", err)
+		}
+		removeDevServerState(o.FrontEndFolderPath)
+		return nil, "", false
+	}
+	process, err := os.FindProcess(state.PID)
+	if err != nil {
+		removeDevServerState(o.FrontEndFolderPath)
+		return nil, "", false
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	d := &devServer{
+		ctx:     ctx,
+		cancel:  cancel,
+		process: process,
+		exited:  make(chan struct{}),
+		stderr:  newLineBuffer(maxStderrLines),
+		keep:    true,
+	}
+	go func() {
+		// the process is not a child of this process, so it can't be waited
+		for processAlive(state.PID) {
+			time.Sleep(500 * time.Millisecond)
+		}
+		close(d.exited)
+	}()
+	if f, err := os.Open(filepath.Join(o.FrontEndFolderPath, devServerLogFile)); err == nil {
+		f.Seek(0, io.SeekEnd)
+		output := newOutputForwarder(o)
+		output.history = history
+		go output.ForwardAll("stdout", &tailReader{ctx: ctx, file: f})
+	}
+	logEvent(o, slog.LevelInfo, "reuse running dev server", "pid", state.PID, "url", state.URL)
+	return d, state.URL, true
+}
+
+func urlAlive(u string) bool {
+	client := &http.Client{
+		Timeout: time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Get(u)
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return true
+}
+
+// StopDevServer stops dev server that is kept running by [Opt].KeepDevServer.
+//
+// It finds frontend folder in the same way as development mode and returns [ErrDevServerNotRunning]
+// if there is no running dev server.
+func StopDevServer(o Opt) error {
+	opt, err := normalizeDevOpt(".", o)
+	if err != nil {
+		return err
+	}
+	state, err := readDevServerState(opt.FrontEndFolderPath)
+	if err != nil {
+		return ErrDevServerNotRunning
+	}
+	defer removeDevServerState(opt.FrontEndFolderPath)
+	if !processAlive(state.PID) {
+		return ErrDevServerNotRunning
+	}
+	return stopKeptDevServer(state.PID)
+}
+
+// stopKeptDevServer sends SIGTERM to the process group of the kept dev server,
+// and SIGKILL if it is still alive after grace period.
+func stopKeptDevServer(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	terminateProcessGroup(process)
+	deadline := time.Now().Add(shutdownGracePeriod)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return killProcessGroup(process)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+// tailReader reads file that is being written like "tail -f" until ctx is done.
+type tailReader struct {
+	ctx  context.Context
+	file *os.File
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.file.Read(p)
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+		select {
+		case <-t.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func (t *tailReader) Close() error {
+	return t.file.Close()
+}
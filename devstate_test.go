@@ -0,0 +1,90 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_KeepDevServer(t *testing.T) {
+	// dummy dev server that answers to liveness check
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+	o := &Opt{
+		FrontEndFolderPath: folder,
+		DevServerCommand:   `sh -c "echo ` + server.URL + `; sleep 30"`,
+		KeepDevServer:      true,
+		SkipInstall:        true,
+	}
+
+	d1, host, err := startDevServer(context.Background(), o, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL, host)
+	d1.Stop()
+	assert.True(t, processAlive(d1.process.Pid), "dev server should be kept")
+
+	state, err := readDevServerState(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, d1.process.Pid, state.PID)
+
+	d2, host, err := startDevServer(context.Background(), o, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL, host)
+	assert.Equal(t, d1.process.Pid, d2.process.Pid, "dev server should be reused")
+	d2.Stop()
+
+	assert.NoError(t, StopDevServer(Opt{FrontEndFolderPath: folder}))
+	assert.False(t, processAlive(d1.process.Pid))
+	_, err = readDevServerState(folder)
+	assert.Error(t, err)
+	assert.ErrorIs(t, StopDevServer(Opt{FrontEndFolderPath: folder}), ErrDevServerNotRunning)
+}
+
+func Test_KeepDevServer_OptionChanged(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+	o := &Opt{
+		FrontEndFolderPath: folder,
+		DevServerCommand:   `sh -c "echo ` + server.URL + `; sleep 30"`,
+		KeepDevServer:      true,
+		SkipInstall:        true,
+		Port:               5173,
+	}
+
+	d1, _, err := startDevServer(context.Background(), o, nil)
+	assert.NoError(t, err)
+	d1.Stop()
+	state, err := readDevServerState(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(5173), state.Port)
+
+	// same options except port that isn't specified
+	o.Port = 0
+	d2, _, err := startDevServer(context.Background(), o, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, d1.process.Pid, d2.process.Pid, "dev server should be reused")
+	d2.Stop()
+
+	o.BasePath = "/console/"
+	d3, _, err := startDevServer(context.Background(), o, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, d1.process.Pid, d3.process.Pid, "dev server should be restarted")
+	assert.False(t, processAlive(d1.process.Pid), "old dev server should be stopped")
+	d3.Stop()
+
+	state, err = readDevServerState(folder)
+	assert.NoError(t, err)
+	assert.Equal(t, "/console/", state.BasePath)
+	assert.NoError(t, StopDevServer(Opt{FrontEndFolderPath: folder}))
+}
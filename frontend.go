@@ -0,0 +1,141 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// Frontend is one frontend application.
+//
+// It holds its own mode, assets, option and dev server, so one process can have
+// several frontends. It is safe for concurrent use.
+type Frontend struct {
+	mode   Mode
+	assets fs.FS
+	opt    Opt
+
+	lock      sync.Mutex
+	handler   http.Handler
+	devServer *supervisor
+}
+
+// New creates [Frontend].
+//
+// If assets is passed, it works in release mode and returns contents from assets.
+// Otherwise it works in development mode and runs frontend's dev server.
+// Only the first assets is used.
+//
+//	f := frontend.New(frontend.Opt{FrameworkType: frontend.VueJS}, asset)
+//	h, err := f.Handler(ctx)
+func New(o Opt, assets ...fs.FS) *Frontend {
+	f := &Frontend{
+		mode: Development,
+		opt:  o,
+	}
+	if len(assets) > 0 && assets[0] != nil {
+		f.mode = Release
+		f.assets = assets[0]
+	}
+	return f
+}
+
+// Mode returns the mode of this frontend.
+func (f *Frontend) Mode() Mode {
+	return f.mode
+}
+
+// Handler returns handler that handles SPA contents.
+//
+// In development mode, the first call runs dev server. It stops when ctx is done or [Frontend.Stop] is called.
+// Later calls return the same handler.
+func (f *Frontend) Handler(ctx context.Context) (http.Handler, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.handler != nil {
+		return f.handler, nil
+	}
+
+	var handler http.Handler
+	switch f.mode {
+	case Release:
+		if err := validateFrameworkType(&f.opt); err != nil {
+			return nil, err
+		}
+		h, err := newStaticHandler(f.assets, normalizeRelOpt(f.opt))
+		if err != nil {
+			return nil, err
+		}
+		handler = h
+	case Development:
+		o, err := normalizeDevOpt(".", f.opt)
+		if errors.Is(err, ErrPackageJsonNotFound) && f.opt.DevServerURL != "" {
+			// external dev server doesn't need frontend folder in this machine
+			o, err = &f.opt, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if o.DevServerURL != "" {
+			u, err := url.Parse(o.DevServerURL)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return nil, fmt.Errorf("invalid DevServerURL '%s'", o.DevServerURL)
+			}
+			handler = newDevHandler(fixedTarget{url: u}, o)
+		} else if !o.SkipRunningDevServer {
+			if c, ok := frameworkConfigs[o.FrameworkType]; ok && o.BasePath != "" && c.BasePathArg == "" {
+				logEvent(o, slog.LevelInfo, "dev server can't get base path by command line option. Use FRONTEND_GO_BASE_PATH environment variable in framework's config file", "basePath", o.BasePath)
+			}
+			s := startSupervisor(ctx, o)
+			f.devServer = s
+			handler = newDevHandler(s, o)
+		} else if o.Port != 0 {
+			u, _ := url.Parse("http://localhost:" + strconv.Itoa(int(o.Port)))
+			handler = newDevHandler(fixedTarget{url: u}, o)
+		} else {
+			h, err := newPreviewHandler(o)
+			if err != nil {
+				return nil, err
+			}
+			handler = h
+		}
+	}
+	f.handler = handler
+	return handler, nil
+}
+
+// HandlerFunc is similar to [Frontend.Handler] but returns handler function.
+func (f *Frontend) HandlerFunc(ctx context.Context) (http.HandlerFunc, error) {
+	h, err := f.Handler(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.ServeHTTP, nil
+}
+
+// setDefaultBasePath sets base path if it is not specified by option.
+func (f *Frontend) setDefaultBasePath(basePath string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.opt.BasePath == "" {
+		f.opt.BasePath = basePath
+	}
+}
+
+// Stop stops dev server if it is running.
+func (f *Frontend) Stop() {
+	f.lock.Lock()
+	d := f.devServer
+	f.devServer = nil
+	f.lock.Unlock()
+	if d != nil {
+		d.Stop()
+	}
+}
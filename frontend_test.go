@@ -0,0 +1,162 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shibukawa/acquire-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontend_SideBySide(t *testing.T) {
+	testDataPaths := acquire.MustAcquire(acquire.Dir, "testdata")
+	assets := fstest.MapFS{
+		"frontend/dist/index.html": {Data: []byte("index")},
+	}
+
+	rel := New(Opt{FrameworkType: VueJS}, assets)
+	dev := New(Opt{
+		FrontEndFolderPath:   filepath.Join(testDataPaths[0], "emptyproject", "frontend"),
+		SkipRunningDevServer: true,
+		Port:                 5173,
+	})
+	assert.Equal(t, Release, rel.Mode())
+	assert.Equal(t, Development, dev.Mode())
+
+	ctx := context.Background()
+	rh, err := rel.Handler(ctx)
+	assert.NoError(t, err)
+	_, err = dev.Handler(ctx)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	rh.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	body, _ := io.ReadAll(w.Result().Body)
+	assert.Equal(t, "index", string(body))
+}
+
+func TestFrontend_DevServerURL(t *testing.T) {
+	devServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("external: " + r.URL.Path))
+	}))
+	defer devServer.Close()
+
+	t.Run("self-signed certificate", func(t *testing.T) {
+		f := New(Opt{
+			FrontEndFolderPath:          t.TempDir(),
+			DevServerURL:                devServer.URL,
+			DevServerInsecureSkipVerify: true,
+		})
+		h, err := f.Handler(context.Background())
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/main.js", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "external: /main.js", w.Body.String())
+	})
+	t.Run("SkipRunningDevServer without Port", func(t *testing.T) {
+		f := New(Opt{
+			FrontEndFolderPath:          t.TempDir(),
+			SkipRunningDevServer:        true,
+			DevServerURL:                devServer.URL,
+			DevServerInsecureSkipVerify: true,
+		})
+		h, err := f.Handler(context.Background())
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, "external: /", w.Body.String())
+	})
+	t.Run("SkipRunningDevServer without Port and DevServerURL", func(t *testing.T) {
+		// no do-nothing handler. It serves dist folder from disk (preview mode) or fails if it isn't built
+		folder := t.TempDir()
+		os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{"dependencies": {"vue": "3.0.0"}}`), 0o644)
+		_, err := New(Opt{FrontEndFolderPath: folder, SkipRunningDevServer: true}).Handler(context.Background())
+		assert.ErrorIs(t, err, ErrFallbackNotFound)
+	})
+	t.Run("verify certificate by default", func(t *testing.T) {
+		f := New(Opt{
+			FrontEndFolderPath: t.TempDir(),
+			DevServerURL:       devServer.URL,
+		})
+		h, err := f.Handler(context.Background())
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/main.js", nil))
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+	t.Run("invalid url", func(t *testing.T) {
+		f := New(Opt{
+			FrontEndFolderPath: t.TempDir(),
+			DevServerURL:       "localhost:5173",
+		})
+		_, err := f.Handler(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestFrontend_Preview(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		p := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	writeFile("package.json", `{"dependencies": {"next": "12.0.0"}}`)
+	writeFile("out/index.html", "index")
+	writeFile("out/about.html", "about")
+
+	f := New(Opt{FrontEndFolderPath: dir, SkipRunningDevServer: true})
+	h, err := f.Handler(context.Background())
+	assert.NoError(t, err)
+
+	get := func(p string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", p, nil))
+		return w
+	}
+	assert.Equal(t, "about", get("/about").Body.String())
+	assert.Equal(t, "index", get("/missing").Body.String())
+
+	// rebuilt file is served with new ETag
+	etag := get("/").Header().Get("ETag")
+	writeFile("out/index.html", "rebuilt")
+	w := get("/")
+	assert.Equal(t, "rebuilt", w.Body.String())
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// files outside of dist folder are not served
+	writeFile(".env", "SECRET=1")
+	for _, p := range []string{"/../.env", "/../../.env", "/about/../../.env", "/..%2f.env"} {
+		assert.NotContains(t, get(p).Body.String(), "SECRET", p)
+	}
+
+	t.Run("not built", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"vue": "3.0.0"}}`), 0o644))
+		_, err := New(Opt{FrontEndFolderPath: dir, SkipRunningDevServer: true}).Handler(context.Background())
+		assert.ErrorIs(t, err, ErrFallbackNotFound)
+	})
+}
+
+func TestFrontend_ReleaseWithoutFrameworkType(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/dist/index.html": {Data: []byte("index")},
+	}
+	assert.NotPanics(t, func() {
+		_, err := New(Opt{}, assets).Handler(context.Background())
+		assert.ErrorIs(t, err, ErrUnknownFrameworkType)
+		assert.Contains(t, err.Error(), "Specify FrameworkType")
+
+		m := NewMux()
+		m.Mount("/app/", New(Opt{}, assets))
+		_, err = m.Handler(context.Background())
+		assert.ErrorIs(t, err, ErrUnknownFrameworkType)
+	})
+}
@@ -1,6 +1,6 @@
 module github.com/shibukawa/frontend-go
 
-go 1.18
+go 1.21
 
 require (
 	github.com/google/shlex v0.0.0-20191202100458-e7afc7fbc510
@@ -1,40 +1,264 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"mime"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 var ErrDir = errors.New("path is dir")
+var ErrFallbackNotFound = errors.New("fallback file not found")
 
-var mode Mode = Development
-
-var frontAssets embed.FS
-var opt Opt
+// defaultFrontend is used by package level functions like [NewSPAHandler].
+var defaultFrontend = New(Opt{})
 
+// SetFrontAsset sets assets and option for package level functions like [NewSPAHandler].
+// It switches them to release mode.
 func SetFrontAsset(assets embed.FS, o Opt) {
-	frontAssets = assets
-	mode = Release
-	opt = o
+	defaultFrontend = New(o, assets)
 }
 
+// SetOption sets option for package level functions like [NewSPAHandler].
 func SetOption(o Opt) {
-	mode = Release
-	opt = o
+	defaultFrontend = New(o, defaultFrontend.assets)
+}
+
+type staticHandler struct {
+	assets fs.FS
+	root   string
+	opt    *Opt
+	etags  sync.Map // file path -> ETag
+	live   bool     // assets are files on disk and can be rebuilt while running
+
+	compressed map[string][]byte // file path -> gzip compressed content
+	nextRoutes []nextRoute       // dynamic routes of Next.js
+}
+
+func newStaticHandler(assets fs.FS, o *Opt) (http.Handler, error) {
+	s := &staticHandler{
+		assets: assets,
+		root:   path.Join(o.FrontEndFolderPath, o.DistFolder),
+		opt:    o,
+	}
+	if !s.exists(o.FallbackPath) {
+		return nil, fmt.Errorf("%w: '%s' is not found in '%s' (DistFolder: '%s'). Check go:embed pattern and build result. Found files: %s",
+			ErrFallbackNotFound, o.FallbackPath, s.root, o.DistFolder, strings.Join(listFiles(assets, maxListedFiles), ", "))
+	}
+	if o.FrameworkType == NextJS {
+		routes, err := buildNextRoutes(assets, s.root)
+		if err != nil {
+			return nil, err
+		}
+		s.nextRoutes = routes
+	}
+	if o.CompressAssets {
+		if err := s.compressAssets(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
 }
 
-func tryRead(prefix, requestedPath string, w http.ResponseWriter) error {
-	f, err := frontAssets.Open(path.Join(prefix, requestedPath))
+// newPreviewHandler returns handler that serves built files in dist folder from disk.
+//
+// It is used in development mode when dev server is skipped, to check production build
+// without embedding it. Dynamic routes of Next.js are collected only at startup.
+func newPreviewHandler(o *Opt) (http.Handler, error) {
+	if err := validateFrameworkType(o); err != nil {
+		return nil, err
+	}
+	ro := normalizeRelOpt(*o)
+	ro.FrontEndFolderPath = "."
+	// compressed contents in memory would be stale after rebuilding
+	ro.CompressAssets = false
+	h, err := newStaticHandler(os.DirFS(o.FrontEndFolderPath), ro)
+	if err != nil {
+		return nil, err
+	}
+	h.(*staticHandler).live = true
+	return h, nil
+}
+
+const maxListedFiles = 20
+
+// listFiles returns file paths in assets for error message.
+func listFiles(assets fs.FS, max int) []string {
+	var files []string
+	fs.WalkDir(assets, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if len(files) > max {
+		files = append(files[:max], "...")
+	}
+	if len(files) == 0 {
+		return []string{"(none)"}
+	}
+	return files
+}
+
+func (s *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	requestedPath := r.URL.Path
+	if s.opt.BasePath != "" && strings.HasPrefix(requestedPath, s.opt.BasePath) {
+		requestedPath = "/" + strings.TrimPrefix(requestedPath, s.opt.BasePath)
+	}
+	// remove ".." not to read files outside of dist folder. Trailing slash is kept for TrailingSlash rule
+	cleanedPath := path.Clean("/" + requestedPath)
+	if strings.HasSuffix(requestedPath, "/") && cleanedPath != "/" {
+		cleanedPath += "/"
+	}
+	requestedPath = cleanedPath
+	if canonicalPath := s.canonicalPath(requestedPath); canonicalPath != requestedPath {
+		u := *r.URL
+		u.Path = path.Join(s.opt.BasePath, canonicalPath)
+		if strings.HasSuffix(canonicalPath, "/") {
+			u.Path += "/"
+		}
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+		return
+	}
+	err := s.tryRead(w, r, requestedPath, http.StatusOK)
+	if err == nil {
+		return
+	}
+	if errors.Is(err, ErrDir) {
+		// prerendered page like "about/index.html"
+		err = s.tryRead(w, r, path.Join(requestedPath, "index.html"), http.StatusOK)
+		if err == nil {
+			return
+		}
+	}
+	if s.opt.FrameworkType == NextJS {
+		// SSG generates .html but request URL may not have extensions
+		err = s.tryRead(w, r, strings.TrimSuffix(requestedPath, "/")+".html", http.StatusOK)
+		if err == nil {
+			return
+		}
+		// dynamic routes like "posts/[id].html"
+		if filePath, ok := s.matchNextRoute(requestedPath); ok {
+			err = s.tryRead(w, r, filePath, http.StatusOK)
+			if err == nil {
+				return
+			}
+		}
+	}
+	switch s.opt.NotFoundPolicy {
+	case NotFoundPage:
+		s.notFound(w, r)
+		return
+	case NotFoundForAssets:
+		if s.isAssetPath(requestedPath) {
+			http.NotFound(w, r)
+			return
+		}
+	}
+	err = s.tryRead(w, r, s.opt.FallbackPath, http.StatusOK)
+	if err != nil {
+		handleError(s.opt, w, r, err)
+	}
+}
+
+// notFound returns framework's 404 page with status 404.
+func (s *staticHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	if s.tryRead(w, r, s.opt.NotFoundPath, http.StatusNotFound) != nil {
+		http.NotFound(w, r)
+	}
+}
+
+// isAssetPath reports whether the path looks like an asset, not a page.
+func (s *staticHandler) isAssetPath(requestedPath string) bool {
+	ext := path.Ext(requestedPath)
+	if ext != "" && ext != ".html" {
+		return true
+	}
+	for _, folder := range s.opt.ImmutableFolders {
+		if strings.HasPrefix(requestedPath, "/"+folder) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorHandlerFunc handles error that occurs while handling request.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// handleError calls [Opt].ErrorHandler. If it is not set, it returns status 500.
+func handleError(o *Opt, w http.ResponseWriter, r *http.Request, err error) {
+	if o.ErrorHandler != nil {
+		o.ErrorHandler(w, r, err)
+		return
+	}
+	log.Printf("frontend: %s %s: %v", r.Method, r.URL.Path, err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// canonicalPath returns the path that should be redirected to by [Opt].TrailingSlash rule.
+func (s *staticHandler) canonicalPath(requestedPath string) string {
+	switch s.opt.TrailingSlash {
+	case TrailingSlashAlways:
+		if !strings.HasSuffix(requestedPath, "/") && s.exists(path.Join(requestedPath, "index.html")) {
+			return requestedPath + "/"
+		}
+	case TrailingSlashNever:
+		if requestedPath == "/" || !strings.HasSuffix(requestedPath, "/") {
+			break
+		}
+		trimmed := strings.TrimSuffix(requestedPath, "/")
+		if s.exists(path.Join(trimmed, "index.html")) || (s.opt.FrameworkType == NextJS && s.exists(trimmed+".html")) {
+			return trimmed
+		}
+	}
+	return requestedPath
+}
+
+// assetPath returns the path in assets. It returns error if the path escapes from dist folder.
+func (s *staticHandler) assetPath(requestedPath string) (string, error) {
+	name := path.Join(s.root, requestedPath)
+	if !fs.ValidPath(name) || (s.root != "." && name != s.root && !strings.HasPrefix(name, s.root+"/")) {
+		return "", fs.ErrNotExist
+	}
+	return name, nil
+}
+
+func (s *staticHandler) exists(requestedPath string) bool {
+	name, err := s.assetPath(requestedPath)
+	if err != nil {
+		return false
+	}
+	stat, err := fs.Stat(s.assets, name)
+	return err == nil && !stat.IsDir()
+}
+
+func (s *staticHandler) tryRead(w http.ResponseWriter, r *http.Request, requestedPath string, status int) error {
+	name, err := s.assetPath(requestedPath)
+	if err != nil {
+		return err
+	}
+	f, err := s.assets.Open(name)
 	if err != nil {
 		return err
 	}
@@ -48,68 +272,85 @@ func tryRead(prefix, requestedPath string, w http.ResponseWriter) error {
 	}
 
 	contentType := mime.TypeByExtension(filepath.Ext(requestedPath))
-	w.Header().Set("Content-Type", contentType)
-	_, err = io.Copy(w, f)
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if cacheControl := s.cacheControl(requestedPath); cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+	rewrite := s.opt.BasePath != "" && s.opt.BasePath != "/" && strings.HasPrefix(contentType, "text/html")
+
+	var body io.Reader = f
+	etag, etagErr := s.etag(requestedPath)
+	if !rewrite {
+		if variants := s.variants(requestedPath); len(variants) > 0 {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if v, ok := negotiateEncoding(r, variants); ok {
+				if v.filePath != "" {
+					cf, err := s.assets.Open(path.Join(s.root, v.filePath))
+					if err != nil {
+						return err
+					}
+					defer cf.Close()
+					body = cf
+					etag, etagErr = s.etag(v.filePath)
+				} else {
+					body = bytes.NewReader(v.content)
+					etag = strings.TrimSuffix(etag, `"`) + "-" + v.encoding + `"`
+				}
+				w.Header().Set("Content-Encoding", v.encoding)
+			}
+		}
+	}
+	if etagErr == nil {
+		w.Header().Set("ETag", etag)
+	}
+	if rewrite {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(rewriteHTML(content, s.opt.BasePath))
+	}
+	content, ok := body.(io.ReadSeeker)
+	if !ok {
+		all, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(all)
+	}
+	if status == http.StatusOK {
+		// handles Range, HEAD, Content-Length and conditional requests
+		http.ServeContent(w, r, requestedPath, time.Time{}, content)
+		return nil
+	}
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, err = io.Copy(w, content)
+	}
 	return err
 }
 
 // NewSPAHandler is handler that handles SPA contents.
 //
+// In development mode, the dev server is stopped when ctx is done. Pass the context that is
+// canceled when Go server exits, like [os/signal.NotifyContext], and call [Stop] to wait for it.
+//
 // Use with net/http:
 //
 //	h, err := NewSPAHandler(ctx)
 //	http.Handle("/", h)
 func NewSPAHandler(ctx context.Context) (http.Handler, error) {
-	var handler http.Handler
-	switch mode {
-	case Release:
-		o := normalizeRelOpt(opt)
-		root := path.Join(o.FrontEndFolderPath, o.DistFolder)
-		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			err := tryRead(root, r.URL.Path, w)
-			if err == nil {
-				return
-			}
-			if o.FrameworkType == NextJS {
-				// SSG generates .html but request URL may not have extensions
-				err = tryRead(root, r.URL.Path+".html", w)
-				if err == nil {
-					return
-				}
-			}
-			err = tryRead(root, "index.html", w)
-			if err != nil {
-				panic(err)
-			}
-		})
-	case Development:
-		o, err := normalizeDevOpt(".", opt)
-		if err != nil {
-			return nil, err
-		}
-		if !o.SkipRunningDevServer {
-			_, host, err := startDevServer(ctx, o.FrontEndFolderPath, o.DevServerCommand)
-			if err != nil {
-				return nil, err
-			}
-			u, err := url.Parse(host)
-			if err != nil {
-				log.Fatal(err)
-			}
-			handler = httputil.NewSingleHostReverseProxy(u)
-		} else if o.Port != 0 {
-			// todo: test
-			u, _ := url.Parse("http://localhost:" + strconv.Itoa(int(o.Port)))
-			handler = httputil.NewSingleHostReverseProxy(u)
-		} else {
-			// todo: test
-			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				// do nothing
-			})
-		}
-	}
-
-	return handler, nil
+	return defaultFrontend.Handler(ctx)
 }
 
 // NewSPAHandlerFunc is handler function that handles SPA contents.
@@ -117,16 +358,10 @@ func NewSPAHandler(ctx context.Context) (http.Handler, error) {
 // Use with chi:
 //
 //	r := chi.NewRouter()
-//	c, err := NewSPAHandlerFunc(ctx)
-//	http.NotFound(h)
+//	h, err := NewSPAHandlerFunc(ctx)
+//	r.NotFound(h)
 func NewSPAHandlerFunc(ctx context.Context) (http.HandlerFunc, error) {
-	h, err := NewSPAHandler(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return func(w http.ResponseWriter, r *http.Request) {
-		h.ServeHTTP(w, r)
-	}, nil
+	return defaultFrontend.HandlerFunc(ctx)
 }
 
 // MustNewSPAHandler is similar to [NewSPAHandler] but this calls panic when error.
@@ -146,3 +381,9 @@ func MustNewSPAHandlerFunc(ctx context.Context) http.HandlerFunc {
 	}
 	return h
 }
+
+// Stop stops the dev server started by [NewSPAHandler] and waits until it exits.
+// Call it before Go server exits. It does nothing in release mode.
+func Stop() {
+	defaultFrontend.Stop()
+}
@@ -0,0 +1,292 @@
+package frontend
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_staticHandler_DirectoryIndex(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/build/index.html":       {Data: []byte("root")},
+		"frontend/build/about/index.html": {Data: []byte("about")},
+		"frontend/build/blog.html":        {Data: []byte("blog")},
+	}
+	tests := []struct {
+		name         string
+		opt          Opt
+		path         string
+		wantStatus   int
+		wantBody     string
+		wantLocation string
+	}{
+		{
+			name:       "directory with trailing slash",
+			opt:        Opt{FrameworkType: SvelteKit},
+			path:       "/about/",
+			wantStatus: 200,
+			wantBody:   "about",
+		},
+		{
+			name:       "directory without trailing slash",
+			opt:        Opt{FrameworkType: SvelteKit},
+			path:       "/about",
+			wantStatus: 200,
+			wantBody:   "about",
+		},
+		{
+			name:         "redirect to trailing slash",
+			opt:          Opt{FrameworkType: SvelteKit, TrailingSlash: TrailingSlashAlways},
+			path:         "/about?q=1",
+			wantStatus:   301,
+			wantLocation: "/about/?q=1",
+		},
+		{
+			name:         "redirect to trailing slash under base path",
+			opt:          Opt{FrameworkType: SvelteKit, TrailingSlash: TrailingSlashAlways, BasePath: "/console/"},
+			path:         "/console/about",
+			wantStatus:   301,
+			wantLocation: "/console/about/",
+		},
+		{
+			name:         "redirect to no trailing slash",
+			opt:          Opt{FrameworkType: SvelteKit, TrailingSlash: TrailingSlashNever},
+			path:         "/about/",
+			wantStatus:   301,
+			wantLocation: "/about",
+		},
+		{
+			name:         "redirect to no trailing slash (Next.js)",
+			opt:          Opt{FrameworkType: NextJS, DistFolder: "build", TrailingSlash: TrailingSlashNever},
+			path:         "/blog/",
+			wantStatus:   301,
+			wantLocation: "/blog",
+		},
+		{
+			name:       "no redirect for SPA route",
+			opt:        Opt{FrameworkType: SvelteKit, TrailingSlash: TrailingSlashAlways},
+			path:       "/users",
+			wantStatus: 200,
+			wantBody:   "root",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := newStaticHandler(assets, normalizeRelOpt(tt.opt))
+			assert.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(w.Result().Body)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+			if tt.wantLocation != "" {
+				assert.Equal(t, tt.wantLocation, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func Test_staticHandler_NotFoundPolicy(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/out/index.html":           {Data: []byte("root")},
+		"frontend/out/404.html":             {Data: []byte("not found")},
+		"frontend/out/_next/static/main.js": {Data: []byte("js")},
+	}
+	tests := []struct {
+		name       string
+		policy     NotFoundPolicy
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "fallback: page", policy: NotFoundFallback, path: "/users", wantStatus: 200, wantBody: "root"},
+		{name: "fallback: asset", policy: NotFoundFallback, path: "/favicon.icoo", wantStatus: 200, wantBody: "root"},
+		{name: "assets: page", policy: NotFoundForAssets, path: "/users", wantStatus: 200, wantBody: "root"},
+		{name: "assets: extension", policy: NotFoundForAssets, path: "/favicon.icoo", wantStatus: 404},
+		{name: "assets: immutable folder", policy: NotFoundForAssets, path: "/_next/static/chunk", wantStatus: 404},
+		{name: "page: page", policy: NotFoundPage, path: "/users", wantStatus: 404, wantBody: "not found"},
+		{name: "page: existing asset", policy: NotFoundPage, path: "/_next/static/main.js", wantStatus: 200, wantBody: "js"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: NextJS, NotFoundPolicy: tt.policy}))
+			assert.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(w.Result().Body)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func Test_staticHandler_PathTraversal(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/dist/index.html":   {Data: []byte("root")},
+		"frontend/dist/about/a.html": {Data: []byte("about")},
+		"frontend/.env":              {Data: []byte("SECRET=1")},
+		"release.go":                 {Data: []byte("package main")},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: VueJS}))
+	assert.NoError(t, err)
+	for _, p := range []string{"/../.env", "/../../release.go", "/about/../../.env", "/%2e%2e/.env"} {
+		t.Run(p, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", p, nil))
+			assert.Equal(t, "root", w.Body.String())
+		})
+	}
+	// ".." inside dist folder is still allowed
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/about/../about/a.html", nil))
+	assert.Equal(t, "about", w.Body.String())
+}
+
+func Test_newStaticHandler_Validation(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/build/index.html": {Data: []byte("root")},
+	}
+	_, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: VueJS}))
+	assert.True(t, errors.Is(err, ErrFallbackNotFound))
+	assert.Contains(t, err.Error(), "frontend/dist")
+	assert.Contains(t, err.Error(), "frontend/build/index.html")
+
+	_, err = newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: SvelteKit}))
+	assert.NoError(t, err)
+}
+
+func Test_staticHandler_ErrorHandler(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/build/index.html": {Data: []byte("root")},
+	}
+	var handledErr error
+	o := normalizeRelOpt(Opt{
+		FrameworkType: SvelteKit,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			handledErr = err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	})
+	h, err := newStaticHandler(assets, o)
+	assert.NoError(t, err)
+	delete(assets, "frontend/build/index.html")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Error(t, handledErr)
+}
+
+func Test_staticHandler_ServeContent(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/dist/index.html":   {Data: []byte("root")},
+		"frontend/dist/movie.mp4":    {Data: []byte("0123456789")},
+		"frontend/dist/404.html":     {Data: []byte("not found")},
+		"frontend/dist/assets/a.css": {Data: []byte("body{}")},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: VueJS}))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/movie.mp4", nil))
+	etag := w.Header().Get("ETag")
+
+	tests := []struct {
+		name       string
+		method     string
+		header     map[string]string
+		opt        *Opt
+		path       string
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "content length",
+			method:     "GET",
+			path:       "/movie.mp4",
+			wantStatus: 200,
+			wantBody:   "0123456789",
+			wantHeader: map[string]string{"Content-Length": "10", "Accept-Ranges": "bytes"},
+		},
+		{
+			name:       "head",
+			method:     "HEAD",
+			path:       "/movie.mp4",
+			wantStatus: 200,
+			wantBody:   "",
+			wantHeader: map[string]string{"Content-Length": "10"},
+		},
+		{
+			name:       "range",
+			method:     "GET",
+			header:     map[string]string{"Range": "bytes=2-5"},
+			path:       "/movie.mp4",
+			wantStatus: 206,
+			wantBody:   "2345",
+			wantHeader: map[string]string{"Content-Range": "bytes 2-5/10"},
+		},
+		{
+			name:       "if-range matches",
+			method:     "GET",
+			header:     map[string]string{"Range": "bytes=8-", "If-Range": etag},
+			path:       "/movie.mp4",
+			wantStatus: 206,
+			wantBody:   "89",
+		},
+		{
+			name:       "if-range doesn't match",
+			method:     "GET",
+			header:     map[string]string{"Range": "bytes=8-", "If-Range": `"old"`},
+			path:       "/movie.mp4",
+			wantStatus: 200,
+			wantBody:   "0123456789",
+		},
+		{
+			name:       "post is not allowed",
+			method:     "POST",
+			path:       "/movie.mp4",
+			wantStatus: 405,
+			wantHeader: map[string]string{"Allow": "GET, HEAD"},
+		},
+		{
+			name:       "head of 404 page",
+			method:     "HEAD",
+			opt:        normalizeRelOpt(Opt{FrameworkType: VueJS, NotFoundPolicy: NotFoundPage}),
+			path:       "/missing",
+			wantStatus: 404,
+			wantBody:   "",
+			wantHeader: map[string]string{"Content-Length": "9"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := h
+			if tt.opt != nil {
+				h, _ = newStaticHandler(assets, tt.opt)
+			}
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != 405 {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+			for k, v := range tt.wantHeader {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
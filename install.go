@@ -0,0 +1,160 @@
+package frontend
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInstallFailed = errors.New("installing dependencies failed")
+
+// installStampFile is written in node_modules after installing. It has the hash of the lockfile.
+const installStampFile = ".frontend-go-install-stamp"
+
+// installMarkers are written in node_modules by package managers when they install.
+// npm, pnpm, yarn berry and yarn classic in this order.
+var installMarkers = []string{".package-lock.json", ".modules.yaml", ".yarn-state.yml", ".yarn-integrity"}
+
+// defaultInstallTimeout is the default value of [Opt].InstallTimeout.
+const defaultInstallTimeout = 5 * time.Minute
+
+// installCommand returns install command and the file that decides whether node_modules is stale.
+// If there is no lockfile, package.json is used with the command that creates lockfile.
+func installCommand(o *Opt) (command []string, file string) {
+	pm := o.PackageManager
+	if pm == PackageManagerAutoDetect {
+		p, _ := readPackageJson(o.FrontEndFolderPath)
+		pm = detectPackageManager(o.FrontEndFolderPath, p)
+	}
+	c := packageManagerConfigs[pm]
+	for _, lockFile := range c.LockFiles {
+		if _, err := os.Stat(filepath.Join(o.FrontEndFolderPath, lockFile)); err == nil {
+			return strings.Fields(c.InstallCommand), lockFile
+		}
+	}
+	return strings.Fields(c.InitCommand), "package.json"
+}
+
+// installStamp returns the hash of the file and the command.
+func installStamp(folder, file string, command []string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(folder, file))
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(strings.Join(command, " ") + "\n"))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// nodeModulesUpToDate reports whether node_modules that is installed without frontend-go
+// is newer than the file. It compares the package manager's marker file in node_modules,
+// or node_modules itself if there is no marker.
+func nodeModulesUpToDate(folder, file string) bool {
+	lockFile, err := os.Stat(filepath.Join(folder, file))
+	if err != nil {
+		return false
+	}
+	installed, err := os.Stat(filepath.Join(folder, "node_modules"))
+	if err != nil || !installed.IsDir() {
+		return false
+	}
+	for _, marker := range installMarkers {
+		if stat, err := os.Stat(filepath.Join(folder, "node_modules", marker)); err == nil {
+			installed = stat
+			break
+		}
+	}
+	return !installed.ModTime().Before(lockFile.ModTime())
+}
+
+// installDependencies runs install command if node_modules is missing or older than the lockfile.
+func installDependencies(ctx context.Context, o *Opt, history *lineBuffer) error {
+	command, file := installCommand(o)
+	stamp, err := installStamp(o.FrontEndFolderPath, file, command)
+	if errors.Is(err, fs.ErrNotExist) {
+		// no package.json, nothing to install
+		return nil
+	} else if err != nil {
+		return err
+	}
+	stampPath := filepath.Join(o.FrontEndFolderPath, "node_modules", installStampFile)
+	current, err := os.ReadFile(stampPath)
+	if err == nil && string(current) == stamp {
+		return nil
+	} else if errors.Is(err, fs.ErrNotExist) && nodeModulesUpToDate(o.FrontEndFolderPath, file) {
+		// installed by hand before using frontend-go. Trust it instead of reinstalling
+		logEvent(o, slog.LevelInfo, "node_modules is up to date", "lockfile", file)
+		return os.WriteFile(stampPath, []byte(stamp), 0o644)
+	}
+	logEvent(o, slog.LevelInfo, "installing dependencies", "command", strings.Join(command, " "), "lockfile", file)
+
+	timeout := o.InstallTimeout
+	if timeout == 0 {
+		timeout = defaultInstallTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	setProcessGroup(cmd)
+	stopWithParent(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd.Process)
+	}
+	// don't wait for grandchildren that keep output open
+	cmd.WaitDelay = time.Second
+	cmd.Dir = o.FrontEndFolderPath
+	stderrLines := newLineBuffer(maxStderrLines)
+	output := newOutputForwarder(o)
+	output.history = history
+	var wg sync.WaitGroup
+	forward := func(stream string) io.WriteCloser {
+		r, w := io.Pipe()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer r.Close()
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				if stream == "stderr" {
+					stderrLines.Add(scanner.Text())
+				}
+				output.Forward(stream, scanner.Text())
+			}
+		}()
+		return w
+	}
+	stdout, stderr := forward("stdout"), forward("stderr")
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err = cmd.Run()
+	stdout.Close()
+	stderr.Close()
+	wg.Wait()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: '%s' in '%s' didn't finish within %s: %s",
+			ErrInstallFailed, strings.Join(command, " "), o.FrontEndFolderPath, timeout, strings.Join(stderrLines.Lines(), "\n"))
+	} else if err != nil {
+		return fmt.Errorf("%w: '%s' in '%s': %v: %s",
+			ErrInstallFailed, strings.Join(command, " "), o.FrontEndFolderPath, err, strings.Join(stderrLines.Lines(), "\n"))
+	}
+	logEvent(o, slog.LevelInfo, "dependencies are installed")
+	// package manager doesn't create node_modules if there are no dependencies
+	if err := os.MkdirAll(filepath.Dir(stampPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(stampPath, []byte(stamp), 0o644)
+}
@@ -0,0 +1,124 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCommand puts the command that runs script into PATH.
+func fakeCommand(t *testing.T, name, script string) {
+	bin := t.TempDir()
+	err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+	assert.NoError(t, err)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func Test_installCommand(t *testing.T) {
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+	command, file := installCommand(&Opt{FrontEndFolderPath: folder})
+	assert.Equal(t, []string{"npm", "install"}, command)
+	assert.Equal(t, "package.json", file)
+
+	os.WriteFile(filepath.Join(folder, "package-lock.json"), []byte("{}"), 0o644)
+	command, file = installCommand(&Opt{FrontEndFolderPath: folder})
+	assert.Equal(t, []string{"npm", "ci"}, command)
+	assert.Equal(t, "package-lock.json", file)
+
+	os.WriteFile(filepath.Join(folder, "pnpm-lock.yaml"), []byte(""), 0o644)
+	command, file = installCommand(&Opt{FrontEndFolderPath: folder})
+	assert.Equal(t, []string{"pnpm", "install", "--frozen-lockfile"}, command)
+	assert.Equal(t, "pnpm-lock.yaml", file)
+
+	command, file = installCommand(&Opt{FrontEndFolderPath: folder, PackageManager: PackageManagerBun})
+	assert.Equal(t, []string{"bun", "install"}, command)
+	assert.Equal(t, "package.json", file)
+}
+
+func Test_installDependencies(t *testing.T) {
+	fakeCommand(t, "npm", `mkdir -p node_modules; echo "$@" >> node_modules/installed; echo installing >&2`)
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+	os.WriteFile(filepath.Join(folder, "package-lock.json"), []byte(`{"v":1}`), 0o644)
+	var out bytes.Buffer
+	o := &Opt{FrontEndFolderPath: folder, DevServerOutput: &out}
+	installed := func() string {
+		b, _ := os.ReadFile(filepath.Join(folder, "node_modules", "installed"))
+		return string(b)
+	}
+
+	assert.NoError(t, installDependencies(context.Background(), o, nil))
+	assert.Equal(t, "ci\n", installed())
+	assert.Contains(t, out.String(), "[AutoDetect:stderr] installing")
+
+	// up to date
+	assert.NoError(t, installDependencies(context.Background(), o, nil))
+	assert.Equal(t, "ci\n", installed())
+
+	// lockfile is updated
+	os.WriteFile(filepath.Join(folder, "package-lock.json"), []byte(`{"v":2}`), 0o644)
+	assert.NoError(t, installDependencies(context.Background(), o, nil))
+	assert.Equal(t, "ci\nci\n", installed())
+}
+
+func Test_installDependencies_noStamp(t *testing.T) {
+	fakeCommand(t, "npm", `mkdir -p node_modules; echo "$@" >> node_modules/installed`)
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+	os.WriteFile(filepath.Join(folder, "package-lock.json"), []byte(`{"v":1}`), 0o644)
+	os.MkdirAll(filepath.Join(folder, "node_modules"), 0o755)
+	marker := filepath.Join(folder, "node_modules", ".package-lock.json")
+	os.WriteFile(marker, []byte(`{"v":1}`), 0o644)
+	o := &Opt{FrontEndFolderPath: folder, DevServerOutput: &bytes.Buffer{}}
+	installed := func() string {
+		b, _ := os.ReadFile(filepath.Join(folder, "node_modules", "installed"))
+		return string(b)
+	}
+	stampPath := filepath.Join(folder, "node_modules", installStampFile)
+
+	t.Run("node_modules present, no stamp", func(t *testing.T) {
+		assert.NoError(t, installDependencies(context.Background(), o, nil))
+		assert.Equal(t, "", installed())
+		_, err := os.Stat(stampPath)
+		assert.NoError(t, err)
+	})
+	t.Run("node_modules is older than lockfile", func(t *testing.T) {
+		os.Remove(stampPath)
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(marker, old, old)
+		assert.NoError(t, installDependencies(context.Background(), o, nil))
+		assert.Equal(t, "ci\n", installed())
+	})
+}
+
+func Test_installDependencies_error(t *testing.T) {
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+
+	t.Run("failed", func(t *testing.T) {
+		fakeCommand(t, "npm", `echo "npm ERR! network" >&2; exit 1`)
+		err := installDependencies(context.Background(), &Opt{FrontEndFolderPath: folder, DevServerOutput: &bytes.Buffer{}}, nil)
+		assert.ErrorIs(t, err, ErrInstallFailed)
+		assert.Contains(t, err.Error(), "npm ERR! network")
+		_, err = os.Stat(filepath.Join(folder, "node_modules", installStampFile))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		fakeCommand(t, "npm", `sleep 30`)
+		start := time.Now()
+		err := installDependencies(context.Background(), &Opt{
+			FrontEndFolderPath: folder,
+			DevServerOutput:    &bytes.Buffer{},
+			InstallTimeout:     100 * time.Millisecond,
+		}, nil)
+		assert.ErrorIs(t, err, ErrInstallFailed)
+		assert.Contains(t, err.Error(), "didn't finish within")
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
@@ -0,0 +1,104 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mux serves several frontends under different path prefixes from one server.
+//
+// Each frontend has its own option, assets, dev server and fallback.
+//
+//	m := frontend.NewMux()
+//	m.Mount("/admin/", frontend.New(frontend.Opt{FrontEndFolderName: "admin"}))
+//	m.Mount("/app/", frontend.New(frontend.Opt{FrontEndFolderName: "app"}))
+//	h, err := m.Handler(ctx)
+type Mux struct {
+	lock   sync.Mutex
+	mounts []mount
+}
+
+type mount struct {
+	prefix   string
+	frontend *Frontend
+}
+
+// NewMux creates [Mux].
+func NewMux() *Mux {
+	return &Mux{}
+}
+
+// Mount registers frontend at the path prefix like "/admin/".
+//
+// If [Opt].BasePath of the frontend is empty, the prefix is used as its base path.
+func (m *Mux) Mount(prefix string, f *Frontend) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	prefix = normalizePrefix(prefix)
+	f.setDefaultBasePath(prefix)
+	m.mounts = append(m.mounts, mount{
+		prefix:   prefix,
+		frontend: f,
+	})
+	// longest prefix wins
+	sort.SliceStable(m.mounts, func(i, j int) bool {
+		return len(m.mounts[i].prefix) > len(m.mounts[j].prefix)
+	})
+}
+
+// Handler returns handler that dispatches requests to mounted frontends.
+//
+// Each frontend receives request path with the prefix and handles it as its base path.
+// Request to the prefix without trailing slash is redirected.
+func (m *Mux) Handler(ctx context.Context) (http.Handler, error) {
+	m.lock.Lock()
+	mounts := make([]mount, len(m.mounts))
+	copy(mounts, m.mounts)
+	m.lock.Unlock()
+
+	handlers := make([]http.Handler, len(mounts))
+	for i, mt := range mounts {
+		h, err := mt.frontend.Handler(ctx)
+		if err != nil {
+			return nil, err
+		}
+		handlers[i] = h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i, mt := range mounts {
+			if strings.HasPrefix(r.URL.Path, mt.prefix) {
+				handlers[i].ServeHTTP(w, r)
+				return
+			}
+			if r.URL.Path+"/" == mt.prefix {
+				u := *r.URL
+				u.Path = mt.prefix
+				http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}), nil
+}
+
+// Stop stops dev servers of all mounted frontends.
+func (m *Mux) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, mt := range m.mounts {
+		mt.frontend.Stop()
+	}
+}
+
+func normalizePrefix(prefix string) string {
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix = prefix + "/"
+	}
+	return prefix
+}
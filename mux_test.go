@@ -0,0 +1,103 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMux(t *testing.T) {
+	assets := fstest.MapFS{
+		"admin/dist/index.html":  {Data: []byte("admin index")},
+		"admin/dist/app.js":      {Data: []byte("admin js")},
+		"app/build/index.html":   {Data: []byte("app index")},
+		"app/build/_app/main.js": {Data: []byte("app js")},
+	}
+	m := NewMux()
+	m.Mount("/admin/", New(Opt{FrameworkType: VueJS, FrontEndFolderName: "admin"}, assets))
+	m.Mount("/app", New(Opt{FrameworkType: SvelteKit, FrontEndFolderPath: "app"}, assets))
+	h, err := m.Handler(context.Background())
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "admin asset", path: "/admin/app.js", wantStatus: 200, wantBody: "admin js"},
+		{name: "admin fallback", path: "/admin/users/1", wantStatus: 200, wantBody: "admin index"},
+		{name: "app asset", path: "/app/_app/main.js", wantStatus: 200, wantBody: "app js"},
+		{name: "app fallback", path: "/app/settings", wantStatus: 200, wantBody: "app index"},
+		{name: "redirect to prefix", path: "/admin", wantStatus: 301},
+		{name: "not mounted", path: "/other", wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(w.Result().Body)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestMux_Development(t *testing.T) {
+	// dummy dev servers that record the requested path
+	newDevServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name + " " + r.URL.Path))
+		}))
+	}
+	admin, app := newDevServer("admin"), newDevServer("app")
+	defer admin.Close()
+	defer app.Close()
+
+	newFrontend := func(framework FrameworkType, server *httptest.Server) (*Frontend, string) {
+		folder := t.TempDir()
+		os.WriteFile(filepath.Join(folder, "package.json"), []byte("{}"), 0o644)
+		return New(Opt{
+			FrontEndFolderPath: folder,
+			FrameworkType:      framework,
+			// records the base path passed to dev server
+			DevServerCommand: `sh -c "echo $FRONTEND_GO_BASE_PATH > base; echo ` + server.URL + `; sleep 30"`,
+			SkipInstall:      true,
+			NodeVersionCheck: NodeVersionCheckSkip,
+		}), folder
+	}
+	adminFrontend, adminFolder := newFrontend(VueJS, admin)
+	appFrontend, appFolder := newFrontend(SvelteKit, app)
+	m := NewMux()
+	m.Mount("/admin/", adminFrontend)
+	m.Mount("/app/", appFrontend)
+	defer m.Stop()
+	h, err := m.Handler(context.Background())
+	assert.NoError(t, err)
+
+	get := func(p string) string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", p, nil))
+		return w.Body.String()
+	}
+	assert.Eventually(t, func() bool {
+		return get("/admin/js/app.js") == "admin /admin/js/app.js" && get("/app/_app/main.js") == "app /app/_app/main.js"
+	}, 5*time.Second, 20*time.Millisecond)
+
+	base := func(folder string) string {
+		b, _ := os.ReadFile(filepath.Join(folder, "base"))
+		return string(b)
+	}
+	assert.Equal(t, "/admin/\n", base(adminFolder))
+	assert.Equal(t, "/app/\n", base(appFolder))
+}
@@ -0,0 +1,126 @@
+package frontend
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+type segmentKind int
+
+// segment kinds in the order of priority
+const (
+	staticSegment segmentKind = iota
+	dynamicSegment
+	catchAllSegment
+	optionalCatchAllSegment
+)
+
+type routeSegment struct {
+	kind segmentKind
+	name string
+}
+
+// nextRoute is a route of Next.js dynamic page like "posts/[id].html".
+type nextRoute struct {
+	segments []routeSegment
+	filePath string
+}
+
+// buildNextRoutes builds route table from exported file names of Next.js.
+// It contains only dynamic routes. Static pages are served directly.
+func buildNextRoutes(assets fs.FS, root string) ([]nextRoute, error) {
+	var routes []nextRoute
+	err := fs.WalkDir(assets, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".html" || !strings.Contains(p, "[") {
+			return err
+		}
+		rel := p
+		if root != "." {
+			rel = strings.TrimPrefix(p, root+"/")
+		}
+		route := strings.TrimSuffix(rel, ".html")
+		route = strings.TrimSuffix(route, "/index")
+		if route == "index" {
+			route = ""
+		}
+		routes = append(routes, nextRoute{
+			segments: parseNextRoute(route),
+			filePath: "/" + rel,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].lessThan(routes[j])
+	})
+	return routes, nil
+}
+
+func parseNextRoute(route string) []routeSegment {
+	var segments []routeSegment
+	for _, s := range strings.Split(route, "/") {
+		switch {
+		case s == "":
+			continue
+		case strings.HasPrefix(s, "[[...") && strings.HasSuffix(s, "]]"):
+			segments = append(segments, routeSegment{kind: optionalCatchAllSegment, name: s[5 : len(s)-2]})
+		case strings.HasPrefix(s, "[...") && strings.HasSuffix(s, "]"):
+			segments = append(segments, routeSegment{kind: catchAllSegment, name: s[4 : len(s)-1]})
+		case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+			segments = append(segments, routeSegment{kind: dynamicSegment, name: s[1 : len(s)-1]})
+		default:
+			segments = append(segments, routeSegment{kind: staticSegment, name: s})
+		}
+	}
+	return segments
+}
+
+// lessThan reports whether r is more specific than o.
+// Like Next.js router, static segment wins over dynamic one, and dynamic one wins over catch-all.
+func (r nextRoute) lessThan(o nextRoute) bool {
+	for i := 0; i < len(r.segments) && i < len(o.segments); i++ {
+		if r.segments[i].kind != o.segments[i].kind {
+			return r.segments[i].kind < o.segments[i].kind
+		}
+	}
+	return len(r.segments) > len(o.segments)
+}
+
+// match reports whether the request path matches the route.
+func (r nextRoute) match(requestedPath string) bool {
+	var parts []string
+	for _, p := range strings.Split(requestedPath, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	for i, s := range r.segments {
+		switch s.kind {
+		case catchAllSegment:
+			return len(parts) > i
+		case optionalCatchAllSegment:
+			return true
+		}
+		if i >= len(parts) {
+			return false
+		}
+		if s.kind == staticSegment && s.name != parts[i] {
+			return false
+		}
+	}
+	return len(parts) == len(r.segments)
+}
+
+// matchNextRoute returns the most specific HTML file for the request path.
+func (s *staticHandler) matchNextRoute(requestedPath string) (string, bool) {
+	for _, r := range s.nextRoutes {
+		if r.match(requestedPath) {
+			return r.filePath, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package frontend
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_staticHandler_NextRoutes(t *testing.T) {
+	assets := fstest.MapFS{
+		"frontend/out/index.html":                  {Data: []byte("root")},
+		"frontend/out/posts/new.html":              {Data: []byte("new post")},
+		"frontend/out/posts/[id].html":             {Data: []byte("post")},
+		"frontend/out/posts/[id]/comments.html":    {Data: []byte("comments")},
+		"frontend/out/blog/[...slug].html":         {Data: []byte("blog")},
+		"frontend/out/docs/[[...slug]].html":       {Data: []byte("docs")},
+		"frontend/out/users/[id]/index.html":       {Data: []byte("user")},
+		"frontend/out/shop/[category]/[item].html": {Data: []byte("item")},
+		"frontend/out/shop/sale/[item].html":       {Data: []byte("sale item")},
+		"frontend/out/_next/static/chunks/main.js": {Data: []byte("js")},
+	}
+	h, err := newStaticHandler(assets, normalizeRelOpt(Opt{FrameworkType: NextJS}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		wantBody string
+	}{
+		{path: "/posts/new", wantBody: "new post"},
+		{path: "/posts/1", wantBody: "post"},
+		{path: "/posts/1/", wantBody: "post"},
+		{path: "/posts/1/comments", wantBody: "comments"},
+		{path: "/posts/1/other", wantBody: "root"},
+		{path: "/posts", wantBody: "root"},
+		{path: "/blog/a/b/c", wantBody: "blog"},
+		{path: "/blog", wantBody: "root"},
+		{path: "/docs", wantBody: "docs"},
+		{path: "/docs/a/b", wantBody: "docs"},
+		{path: "/users/1", wantBody: "user"},
+		{path: "/shop/food/apple", wantBody: "item"},
+		{path: "/shop/sale/apple", wantBody: "sale item"},
+		{path: "/_next/static/chunks/main.js", wantBody: "js"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
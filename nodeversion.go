@@ -0,0 +1,272 @@
+package frontend
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNodeNotFound        = errors.New("node is not found in PATH")
+	ErrNodeVersionMismatch = errors.New("node version doesn't match")
+)
+
+// nodeVersionFiles are files that specify Node.js version for nvm, fnm, nodenv and so on.
+var nodeVersionFiles = []string{".nvmrc", ".node-version"}
+
+// nodeRequirement is the Node.js version that the frontend project requires.
+type nodeRequirement struct {
+	Source string // "engines.node of package.json", ".nvmrc" or ".node-version"
+	Range  string
+}
+
+// nodeRequirements reads required Node.js versions from package.json and version files.
+func nodeRequirements(folder string, p *packageJson) []nodeRequirement {
+	var result []nodeRequirement
+	if p != nil && p.Engines.Node != "" {
+		result = append(result, nodeRequirement{Source: "engines.node of package.json", Range: p.Engines.Node})
+	}
+	for _, file := range nodeVersionFiles {
+		if v, ok := readNodeVersionFile(filepath.Join(folder, file)); ok {
+			result = append(result, nodeRequirement{Source: file, Range: v})
+		}
+	}
+	return result
+}
+
+// readNodeVersionFile returns the version in .nvmrc or .node-version.
+// Aliases like "lts/*" and "node" can't be checked without network, so they are ignored.
+func readNodeVersionFile(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, ok := parseVersion(line); !ok {
+			return "", false
+		}
+		return line, true
+	}
+	return "", false
+}
+
+// nodeVersion returns the version of node command in PATH.
+//
+// It runs in the frontend folder because version managers like volta, asdf and nodenv
+// choose node version by the files in the current folder.
+func nodeVersion(folder string) (string, error) {
+	cmd := exec.Command("node", "--version")
+	cmd.Dir = folder
+	out, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", ErrNodeNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("'node --version' failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// checkNodeVersion compares installed Node.js with the versions that the frontend project requires.
+//
+// Mismatch is logged as warning, or returned as error if [Opt].NodeVersionCheck is NodeVersionCheckError.
+func checkNodeVersion(o *Opt) error {
+	if o.NodeVersionCheck == NodeVersionCheckSkip {
+		return nil
+	}
+	p, err := readPackageJson(o.FrontEndFolderPath)
+	if err != nil {
+		// no package.json, nothing to check
+		return nil
+	}
+	requirements := nodeRequirements(o.FrontEndFolderPath, p)
+	installed, err := nodeVersion(o.FrontEndFolderPath)
+	if errors.Is(err, ErrNodeNotFound) {
+		if o.PackageManager == PackageManagerBun {
+			// bun can run dev server without node
+			return nil
+		}
+		// custom command like "deno task dev" may not need node
+		if len(requirements) > 0 || usesDefaultDevCommand(o) {
+			return err
+		}
+		logEvent(o, slog.LevelWarn, err.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+	v, _, ok := parseVersion(installed)
+	if !ok {
+		return fmt.Errorf("unknown node version: '%s'", installed)
+	}
+	for _, r := range requirements {
+		match, ok := satisfiesVersion(v, r.Range)
+		if !ok || match {
+			continue
+		}
+		err := fmt.Errorf("%w: Node.js %s is installed, but %s requires '%s'", ErrNodeVersionMismatch, installed, r.Source, r.Range)
+		if o.NodeVersionCheck == NodeVersionCheckError {
+			return err
+		}
+		logEvent(o, slog.LevelWarn, err.Error())
+	}
+	return nil
+}
+
+// usesDefaultDevCommand reports whether the dev server is run by the package manager's default command.
+func usesDefaultDevCommand(o *Opt) bool {
+	config, ok := frameworkConfigs[o.FrameworkType]
+	return ok && o.DevServerCommand == o.PackageManager.runCommand(config.DevScript)
+}
+
+// version is major, minor and patch.
+type version [3]int
+
+func (v version) less(o version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+// bump increments the last specified part. parts is the count of specified parts.
+func (v version) bump(parts int) version {
+	v[parts-1]++
+	for i := parts; i < len(v); i++ {
+		v[i] = 0
+	}
+	return v
+}
+
+// parseVersion parses version like "v18.17.0", "18.x" and "18".
+// parts is the count of specified parts. Wildcard and missing parts are treated as 0.
+func parseVersion(s string) (v version, parts int, ok bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "="), "v")
+	// ignore prerelease and build metadata
+	if i := strings.IndexAny(s, "-+"); i != -1 {
+		s = s[:i]
+	}
+	if s == "" {
+		return v, 0, false
+	}
+	for i, part := range strings.Split(s, ".") {
+		if i >= len(v) {
+			return v, 0, false
+		}
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, false
+		}
+		v[i] = n
+		parts = i + 1
+	}
+	return v, parts, true
+}
+
+var operatorSpace = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+
+// satisfiesVersion checks v matches the semver range like ">=18", "^18.17.0 || >=20" and "16 - 20".
+// ok is false if the range can't be parsed.
+func satisfiesVersion(v version, versionRange string) (match, ok bool) {
+	for _, alt := range strings.Split(versionRange, "||") {
+		alt = strings.TrimSpace(alt)
+		var m bool
+		if lo, hi, found := strings.Cut(alt, " - "); found {
+			m, ok = satisfiesComparator(v, ">="+lo)
+			if ok && m {
+				m, ok = satisfiesComparator(v, "<="+hi)
+			}
+		} else {
+			m, ok = true, true
+			for _, c := range strings.Fields(operatorSpace.ReplaceAllString(alt, "$1")) {
+				var cm bool
+				cm, ok = satisfiesComparator(v, c)
+				if !ok {
+					break
+				}
+				m = m && cm
+			}
+		}
+		if !ok {
+			return false, false
+		}
+		if m {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func satisfiesComparator(v version, comparator string) (match, ok bool) {
+	op := ""
+	for _, o := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(comparator, o) {
+			op = o
+			break
+		}
+	}
+	target, parts, ok := parseVersion(strings.TrimPrefix(comparator, op))
+	if !ok {
+		return false, false
+	}
+	if parts == 0 {
+		// "*" or "x"
+		return true, true
+	}
+	switch op {
+	case "", "=":
+		if parts == len(target) {
+			return v == target, true
+		}
+		return !v.less(target) && v.less(target.bump(parts)), true
+	case ">=":
+		return !v.less(target), true
+	case ">":
+		if parts == len(target) {
+			return target.less(v), true
+		}
+		return !v.less(target.bump(parts)), true
+	case "<":
+		return v.less(target), true
+	case "<=":
+		if parts == len(target) {
+			return !target.less(v), true
+		}
+		return v.less(target.bump(parts)), true
+	case "^":
+		// the left-most non-zero part must be the same
+		upper := target.bump(1)
+		if target[0] == 0 && parts > 1 {
+			if target[1] != 0 || parts == 2 {
+				upper = target.bump(2)
+			} else {
+				upper = target.bump(3)
+			}
+		}
+		return !v.less(target) && v.less(upper), true
+	case "~":
+		upper := target.bump(1)
+		if parts > 1 {
+			upper = target.bump(2)
+		}
+		return !v.less(target) && v.less(upper), true
+	}
+	return false, false
+}
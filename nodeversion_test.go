@@ -0,0 +1,122 @@
+package frontend
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_satisfiesVersion(t *testing.T) {
+	tests := []struct {
+		versionRange string
+		version      string
+		want         bool
+		wantOk       bool
+	}{
+		{">=18", "v18.0.0", true, true},
+		{">=18", "v16.20.2", false, true},
+		{">= 18.17.0", "v18.16.1", false, true},
+		{">16", "v16.20.0", false, true},
+		{">16", "v17.0.0", true, true},
+		{"<20", "v20.0.0", false, true},
+		{"<=20", "v20.11.0", true, true},
+		{"^18.17.0", "v18.19.0", true, true},
+		{"^18.17.0", "v20.0.0", false, true},
+		{"^0.12.0", "v0.13.0", false, true},
+		{"~18.17.0", "v18.18.0", false, true},
+		{"~18.17.0", "v18.17.5", true, true},
+		{"18.x", "v18.5.0", true, true},
+		{"18", "v19.0.0", false, true},
+		{"18.17.0", "v18.17.0", true, true},
+		{"18.17.0", "v18.17.1", false, true},
+		{">=14 <19", "v18.0.0", true, true},
+		{">=14 <19", "v20.0.0", false, true},
+		{"^16.14.0 || >=18", "v16.15.0", true, true},
+		{"^16.14.0 || >=18", "v17.0.0", false, true},
+		{"^16.14.0 || >=18", "v14.0.0", false, true},
+		{"16 - 18", "v18.9.0", true, true},
+		{"16 - 18", "v19.0.0", false, true},
+		{"*", "v4.0.0", true, true},
+		{"", "v4.0.0", true, true},
+		{"lts/*", "v20.0.0", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.versionRange+" "+tt.version, func(t *testing.T) {
+			v, _, ok := parseVersion(tt.version)
+			assert.True(t, ok)
+			got, gotOk := satisfiesVersion(v, tt.versionRange)
+			assert.Equal(t, tt.wantOk, gotOk)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_checkNodeVersion(t *testing.T) {
+	fakeCommand(t, "node", `echo v16.20.2`)
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{"engines": {"node": ">=18"}}`), 0o644)
+
+	t.Run("warn", func(t *testing.T) {
+		var log bytes.Buffer
+		err := checkNodeVersion(&Opt{FrontEndFolderPath: folder, Logger: slog.New(slog.NewTextHandler(&log, nil))})
+		assert.NoError(t, err)
+		assert.Contains(t, log.String(), "Node.js v16.20.2 is installed, but engines.node of package.json requires '>=18'")
+	})
+	t.Run("error", func(t *testing.T) {
+		err := checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckError})
+		assert.ErrorIs(t, err, ErrNodeVersionMismatch)
+	})
+	t.Run("nvmrc", func(t *testing.T) {
+		folder := t.TempDir()
+		os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{}`), 0o644)
+		os.WriteFile(filepath.Join(folder, ".nvmrc"), []byte("# comment\nv20\n"), 0o644)
+		err := checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckError})
+		assert.ErrorIs(t, err, ErrNodeVersionMismatch)
+		assert.Contains(t, err.Error(), ".nvmrc requires 'v20'")
+
+		os.WriteFile(filepath.Join(folder, ".nvmrc"), []byte("lts/*\n"), 0o644)
+		assert.NoError(t, checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckError}))
+	})
+	t.Run("node-version", func(t *testing.T) {
+		folder := t.TempDir()
+		os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{}`), 0o644)
+		os.WriteFile(filepath.Join(folder, ".node-version"), []byte("16.20"), 0o644)
+		assert.NoError(t, checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckError}))
+	})
+	t.Run("version manager", func(t *testing.T) {
+		// shim chooses node version by .node-version in the current folder
+		fakeCommand(t, "node", `cat .node-version 2>/dev/null || echo v16.20.2`)
+		folder := t.TempDir()
+		os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{}`), 0o644)
+		os.WriteFile(filepath.Join(folder, ".node-version"), []byte("v20.11.0"), 0o644)
+		assert.NoError(t, checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckError}))
+	})
+	t.Run("node not found", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		err := checkNodeVersion(&Opt{FrontEndFolderPath: folder})
+		assert.ErrorIs(t, err, ErrNodeNotFound)
+		assert.NoError(t, checkNodeVersion(&Opt{FrontEndFolderPath: folder, NodeVersionCheck: NodeVersionCheckSkip}))
+
+		noRequirement := t.TempDir()
+		os.WriteFile(filepath.Join(noRequirement, "package.json"), []byte(`{}`), 0o644)
+		// default command runs node
+		err = checkNodeVersion(&Opt{FrontEndFolderPath: noRequirement, FrameworkType: VueJS, DevServerCommand: "npm run serve"})
+		assert.ErrorIs(t, err, ErrNodeNotFound)
+		// custom command may not need node
+		var log bytes.Buffer
+		err = checkNodeVersion(&Opt{
+			FrontEndFolderPath: noRequirement,
+			FrameworkType:      VueJS,
+			DevServerCommand:   "deno task dev",
+			Logger:             slog.New(slog.NewTextHandler(&log, nil)),
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, log.String(), "node is not found in PATH")
+	})
+}
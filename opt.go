@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/shibukawa/acquire-go"
 )
 
 var ErrPackageJsonNotFound = errors.New("package.json not found")
+var ErrUnknownFrameworkType = errors.New("framework type is unknown")
 
 // Opt specifies frontend configuration.
 //
@@ -19,21 +24,84 @@ var ErrPackageJsonNotFound = errors.New("package.json not found")
 // If you changed dist folder or build scripts and so on, use Opt and pass to
 // [NewSPAHandler], [NewSPAHandlerFunc]
 type Opt struct {
-	FrontEndFolderName   string        // Frontend application folder name that contains package.json. Default value is "frontend"
-	FrontEndFolderPath   string        // Absolute frontend application folder that contains package.json.
-	SkipRunningDevServer bool          // Even if development mode, frontend-go doesn't run dev server
-	FrameworkType        FrameworkType // NextJS, VueJS, SvelteKit, SolidJS is available instead of auto detect
-	DistFolder           string        // Specify dist folder instead of auto detect
-	Port                 uint16        // Specify port instead of auto detect
-	DevServerCommand     string        // Specify dev server command instead of auto detect
-	FallbackPath         string        // Specify fallback file path. Default is "index.html"
+	FrontEndFolderName   string           // Frontend application folder name that contains package.json. Default value is "frontend"
+	FrontEndFolderPath   string           // Absolute frontend application folder that contains package.json.
+	SkipRunningDevServer bool             // Even if development mode, frontend-go doesn't run dev server. Built files in DistFolder are served if Port and DevServerURL are not set
+	FrameworkType        FrameworkType    // NextJS, VueJS, SvelteKit, SolidJS is available instead of auto detect
+	PackageManager       PackageManager   // PackageManagerNpm, PackageManagerPnpm, PackageManagerYarn, PackageManagerBun is available instead of auto detect
+	DistFolder           string           // Specify dist folder instead of auto detect
+	Port                 uint16           // Port that is passed to dev server. Default is a free port
+	DevServerCommand     string           // Specify dev server command instead of auto detect
+	FallbackPath         string           // Specify fallback file path. Default is "index.html"
+	BasePath             string           // Path prefix that frontend is mounted at like "/console/". Default is "/"
+	TrailingSlash        TrailingSlash    // Canonical redirect rule of trailing slash. Default is TrailingSlashAsIs
+	NotFoundPolicy       NotFoundPolicy   // How to respond missing path. Default is NotFoundFallback
+	NotFoundPath         string           // Specify 404 page file path. Default is "404.html"
+	ImmutableFolders     []string         // Specify folders that contain hashed assets instead of auto detect
+	ErrorHandler         ErrorHandlerFunc // Called when error occurs while handling request. Default returns status 500
+	CacheControl         CacheControlFunc // Specify Cache-Control header value instead of default rules
+	CompressAssets       bool             // Compress assets by gzip in memory at startup if there are no precompressed .br, .gz, .zst files
+	DevServerTimeout     time.Duration    // Timeout to wait for dev server printing its URL. Default is 1 minute
+	DevServerOutput      io.Writer        // Dev server's stdout and stderr are written with prefix. Default is os.Stdout
+	Logger               *slog.Logger     // If it is set, dev server's output is logged by it instead of DevServerOutput
+	DevServerMaxRestarts int              // Max count to restart crashed dev server. Default is 5. Negative value disables restarting
+	KeepDevServer        bool             // Keep dev server running after Go server exits and reuse it next time. Use StopDevServer() to stop it
+	SkipInstall          bool             // Don't install dependencies even if node_modules is missing or older than the lockfile
+	InstallTimeout       time.Duration    // Timeout to install dependencies. Default is 5 minutes
+	NodeVersionCheck     NodeVersionCheck // How to handle node version that doesn't match engines.node, .nvmrc or .node-version. Default is NodeVersionCheckWarn
+	HMRClientPort        uint16           // Port that browser connects HMR websocket to. Set Go server's port to expose only it. It is passed by --public option (vue-cli) and FRONTEND_GO_HMR_CLIENT_PORT environment variable
+	DevServerURL         string           // URL of dev server that is run by others like "http://web:5173". If it is set, frontend-go doesn't run dev server
+
+	DevServerInsecureSkipVerify bool // Skip TLS certificate verification of DevServerURL for self-signed certificate
 }
 
 type packageJson struct {
+	PackageManager  string            `json:"packageManager"`
+	Engines         packageEngines    `json:"engines"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
 }
 
+type packageEngines struct {
+	Node string `json:"node"`
+}
+
+func readPackageJson(folder string) (*packageJson, error) {
+	packageJsonPath := filepath.Join(folder, "package.json")
+	f, err := os.Open(packageJsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("file open error: '%s'", packageJsonPath)
+	}
+	defer f.Close()
+	var p packageJson
+	err = json.NewDecoder(f).Decode(&p)
+	if err != nil {
+		return nil, fmt.Errorf("json parse error: '%s'", packageJsonPath)
+	}
+	return &p, nil
+}
+
+// detectPackageManager detects package manager from packageManager field like "pnpm@8.6.0"
+// or lockfile in folder. Default is npm.
+func detectPackageManager(folder string, p *packageJson) PackageManager {
+	if p != nil && p.PackageManager != "" {
+		name, _, _ := strings.Cut(p.PackageManager, "@")
+		for pm, c := range packageManagerConfigs {
+			if c.Name == name {
+				return pm
+			}
+		}
+	}
+	for _, pm := range lockFilePriority {
+		for _, lockFile := range packageManagerConfigs[pm].LockFiles {
+			if _, err := os.Stat(filepath.Join(folder, lockFile)); err == nil {
+				return pm
+			}
+		}
+	}
+	return PackageManagerNpm
+}
+
 func (p packageJson) Has(f string) bool {
 	if _, ok := p.Dependencies[f]; ok {
 		return true
@@ -42,19 +110,48 @@ func (p packageJson) Has(f string) bool {
 	return ok
 }
 
+func normalizeBasePath(opt *Opt) {
+	if opt.BasePath != "" {
+		opt.BasePath = normalizePrefix(opt.BasePath)
+	}
+}
+
+// validateFrameworkType checks the framework type has default config that is needed to serve dist folder.
+// In release mode, it can't be detected because package.json is not embedded.
+func validateFrameworkType(o *Opt) error {
+	if _, ok := frameworkConfigs[o.FrameworkType]; !ok {
+		return fmt.Errorf("%w: '%s'. Specify FrameworkType (NextJS, VueJS, SvelteKit or SolidJS) in Opt to serve dist folder", ErrUnknownFrameworkType, o.FrameworkType)
+	}
+	return nil
+}
+
 func normalizeRelOpt(opt Opt) *Opt {
+	normalizeBasePath(&opt)
 	if opt.FrontEndFolderPath == "" {
-		opt.FrontEndFolderPath = "frontend"
+		if opt.FrontEndFolderName != "" {
+			opt.FrontEndFolderPath = opt.FrontEndFolderName
+		} else {
+			opt.FrontEndFolderPath = "frontend"
+		}
 	}
 	if opt.FrontEndFolderName == "" {
 		_, opt.FrontEndFolderName = filepath.Split(opt.FrontEndFolderPath)
 	}
+	if opt.FallbackPath == "" {
+		opt.FallbackPath = "index.html"
+	}
+	if opt.NotFoundPath == "" {
+		opt.NotFoundPath = "404.html"
+	}
 	if defaultConfig, ok := frameworkConfigs[opt.FrameworkType]; ok {
 		if opt.DistFolder == "" {
 			opt.DistFolder = defaultConfig.DistFolder
 		}
 		if opt.DevServerCommand == "" {
-			opt.DevServerCommand = defaultConfig.DevServerCommand
+			opt.DevServerCommand = opt.PackageManager.runCommand(defaultConfig.DevScript)
+		}
+		if opt.ImmutableFolders == nil {
+			opt.ImmutableFolders = defaultConfig.ImmutableFolders
 		}
 	} else {
 		panic("invalid framework type is specified: " + opt.FrameworkType.String())
@@ -63,6 +160,7 @@ func normalizeRelOpt(opt Opt) *Opt {
 }
 
 func normalizeDevOpt(currentFolder string, opt Opt) (*Opt, error) {
+	normalizeBasePath(&opt)
 	if opt.FrontEndFolderName == "" && opt.FrontEndFolderPath == "" {
 		opt.FrontEndFolderName = "frontend"
 	}
@@ -83,18 +181,18 @@ func normalizeDevOpt(currentFolder string, opt Opt) (*Opt, error) {
 	if opt.FallbackPath == "" {
 		opt.FallbackPath = "index.html"
 	}
-	if opt.FrameworkType == AutoDetect {
-		packageJsonPath := filepath.Join(opt.FrontEndFolderPath, "package.json")
-		f, err := os.Open(packageJsonPath)
-		if err != nil {
-			return nil, fmt.Errorf("file open error: '%s'", packageJsonPath)
-		}
-		r := json.NewDecoder(f)
-		var p packageJson
-		err = r.Decode(&p)
+	var p *packageJson
+	if opt.FrameworkType == AutoDetect || opt.PackageManager == PackageManagerAutoDetect {
+		var err error
+		p, err = readPackageJson(opt.FrontEndFolderPath)
 		if err != nil {
-			return nil, fmt.Errorf("json parse error: '%s'", packageJsonPath)
+			return nil, err
 		}
+	}
+	if opt.PackageManager == PackageManagerAutoDetect {
+		opt.PackageManager = detectPackageManager(opt.FrontEndFolderPath, p)
+	}
+	if opt.FrameworkType == AutoDetect {
 		if p.Has("@sveltejs/kit") {
 			opt.FrameworkType = SvelteKit
 		} else if p.Has("next") {
@@ -112,7 +210,7 @@ func normalizeDevOpt(currentFolder string, opt Opt) (*Opt, error) {
 			opt.DistFolder = defaultConfig.DistFolder
 		}
 		if opt.DevServerCommand == "" {
-			opt.DevServerCommand = defaultConfig.DevServerCommand
+			opt.DevServerCommand = opt.PackageManager.runCommand(defaultConfig.DevScript)
 		}
 	}
 	return &opt, nil
@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -30,6 +31,7 @@ func Test_normalizeOpt(t *testing.T) {
 			wantErr: nil,
 			want: &Opt{
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(testDataPaths[0], "emptyproject", "frontend"),
 				DevServerCommand:   "",
 				FrameworkType:      NotFound,
@@ -55,6 +57,7 @@ func Test_normalizeOpt(t *testing.T) {
 				FrameworkType:        SvelteKit,
 				DistFolder:           ".dist",
 				FrontEndFolderName:   "web",
+				PackageManager:       PackageManagerNpm,
 				FrontEndFolderPath:   filepath.Join(testDataPaths[0], "emptyproject", "web"),
 				Port:                 3000,
 				SkipRunningDevServer: true,
@@ -71,6 +74,7 @@ func Test_normalizeOpt(t *testing.T) {
 			wantErr: nil,
 			want: &Opt{
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(testDataPaths[0], "emptyproject", "frontend"),
 				DevServerCommand:   "",
 				FrameworkType:      NotFound,
@@ -88,6 +92,7 @@ func Test_normalizeOpt(t *testing.T) {
 			wantErr: nil,
 			want: &Opt{
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(testDataPaths[0], "emptyproject", "frontend"),
 				DevServerCommand:   "",
 				FrameworkType:      NotFound,
@@ -145,6 +150,7 @@ func Test_normalizeOpt_Detect(t *testing.T) {
 				FrameworkType:      SvelteKit,
 				DistFolder:         "build",
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(samplesPaths[0], "sveltekit", "frontend"),
 				DevServerCommand:   "npm run dev",
 				FallbackPath:       "index.html",
@@ -157,6 +163,7 @@ func Test_normalizeOpt_Detect(t *testing.T) {
 				FrameworkType:      NextJS,
 				DistFolder:         "out",
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(samplesPaths[0], "nextjs", "frontend"),
 				DevServerCommand:   "npm run dev",
 				FallbackPath:       "index.html",
@@ -169,6 +176,7 @@ func Test_normalizeOpt_Detect(t *testing.T) {
 				FrameworkType:      VueJS,
 				DistFolder:         "dist",
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerNpm,
 				FrontEndFolderPath: filepath.Join(samplesPaths[0], "vuejs", "frontend"),
 				DevServerCommand:   "npm run serve",
 				FallbackPath:       "index.html",
@@ -181,8 +189,9 @@ func Test_normalizeOpt_Detect(t *testing.T) {
 				FrameworkType:      SolidJS,
 				DistFolder:         "dist",
 				FrontEndFolderName: "frontend",
+				PackageManager:     PackageManagerPnpm,
 				FrontEndFolderPath: filepath.Join(samplesPaths[0], "solidjs", "frontend"),
-				DevServerCommand:   "npm run dev",
+				DevServerCommand:   "pnpm run dev",
 				FallbackPath:       "index.html",
 			},
 		},
@@ -199,3 +208,29 @@ func Test_normalizeOpt_Detect(t *testing.T) {
 		})
 	}
 }
+
+func Test_detectPackageManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		lockFiles []string
+		want      PackageManager
+	}{
+		{name: "default", want: PackageManagerNpm},
+		{name: "package-lock.json", lockFiles: []string{"package-lock.json"}, want: PackageManagerNpm},
+		{name: "yarn.lock", lockFiles: []string{"yarn.lock"}, want: PackageManagerYarn},
+		{name: "bun.lockb", lockFiles: []string{"bun.lockb"}, want: PackageManagerBun},
+		{name: "pnpm-lock.yaml wins", lockFiles: []string{"package-lock.json", "pnpm-lock.yaml"}, want: PackageManagerPnpm},
+		{name: "packageManager field wins", field: "yarn@4.0.2", lockFiles: []string{"package-lock.json"}, want: PackageManagerYarn},
+		{name: "unknown packageManager field", field: "unknown@1.0.0", lockFiles: []string{"bun.lockb"}, want: PackageManagerBun},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder := t.TempDir()
+			for _, f := range tt.lockFiles {
+				os.WriteFile(filepath.Join(folder, f), nil, 0o644)
+			}
+			assert.Equal(t, tt.want, detectPackageManager(folder, &packageJson{PackageManager: tt.field}))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package frontend
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// outputForwarder forwards dev server's output to [Opt].DevServerOutput or [Opt].Logger.
+type outputForwarder struct {
+	lock      sync.Mutex
+	writer    io.Writer
+	logger    *slog.Logger
+	framework string
+	stripANSI bool
+	history   *lineBuffer // records output for error page if it is not nil
+}
+
+func newOutputForwarder(o *Opt) *outputForwarder {
+	f := &outputForwarder{
+		writer:    o.DevServerOutput,
+		logger:    o.Logger,
+		framework: o.FrameworkType.String(),
+	}
+	if f.writer == nil {
+		f.writer = os.Stdout
+	}
+	// keep colors only when human sees them
+	f.stripANSI = !isTerminal(f.writer)
+	return f
+}
+
+// Forward writes a line of the stream ("stdout" or "stderr").
+func (f *outputForwarder) Forward(stream, line string) {
+	if f.history != nil {
+		f.history.Add(stripANSI(line))
+	}
+	if f.logger != nil {
+		level := slog.LevelInfo
+		if stream == "stderr" {
+			level = slog.LevelWarn
+		}
+		f.logger.Log(context.Background(), level, stripANSI(line), "framework", f.framework, "stream", stream)
+		return
+	}
+	if f.stripANSI {
+		line = stripANSI(line)
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	io.WriteString(f.writer, "["+f.framework+":"+stream+"] "+line+"\n")
+}
+
+// ForwardAll forwards all lines of r.
+func (f *outputForwarder) ForwardAll(stream string, r io.ReadCloser) {
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		f.Forward(stream, scanner.Text())
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// logEvent logs frontend-go's event by [Opt].Logger or slog's default logger.
+func logEvent(o *Opt, level slog.Level, msg string, args ...any) {
+	logger := o.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(context.Background(), level, "frontend: "+msg, append([]any{"framework", o.FrameworkType.String()}, args...)...)
+}
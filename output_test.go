@@ -0,0 +1,30 @@
+package frontend
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_outputForwarder(t *testing.T) {
+	var buf bytes.Buffer
+	f := newOutputForwarder(&Opt{FrameworkType: SvelteKit, DevServerOutput: &buf})
+	f.Forward("stdout", "\x1b[32mready\x1b[39m")
+	f.Forward("stderr", "error")
+	assert.Equal(t, "[SvelteKit:stdout] ready\n[SvelteKit:stderr] error\n", buf.String())
+
+	buf.Reset()
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	f = newOutputForwarder(&Opt{FrameworkType: VueJS, Logger: logger})
+	f.Forward("stderr", "\x1b[31mfailed to compile\x1b[39m")
+	assert.Equal(t, "level=WARN msg=\"failed to compile\" framework=VueJS stream=stderr\n", buf.String())
+}
@@ -0,0 +1,156 @@
+package frontend
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultMaxRestarts is the default value of [Opt].DevServerMaxRestarts.
+	defaultMaxRestarts = 5
+	minRestartBackoff  = time.Second
+	maxRestartBackoff  = 30 * time.Second
+	// restartResetAfter is the running time after that the dev server is treated as stable.
+	restartResetAfter = time.Minute
+	// maxHistoryLines is the number of output lines that is shown in error page.
+	maxHistoryLines = 50
+)
+
+// supervisor runs dev server and restarts it when it crashes.
+type supervisor struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	opt     *Opt
+	target  atomic.Pointer[url.URL]
+	failure atomic.Pointer[devServerFailure] // the last failure. nil after dev server gets ready
+	history *lineBuffer
+	done    chan struct{} // closed when watch loop finishes
+
+	lock    sync.Mutex
+	current *devServer
+}
+
+// startSupervisor runs dev server in background. It returns immediately.
+// [supervisor.Target] returns nil until the dev server gets ready.
+func startSupervisor(ctx context.Context, o *Opt) *supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &supervisor{
+		ctx:     ctx,
+		cancel:  cancel,
+		opt:     o,
+		history: newLineBuffer(maxHistoryLines),
+		done:    make(chan struct{}),
+	}
+	go s.watch()
+	return s
+}
+
+func (s *supervisor) start() (*devServer, error) {
+	d, host, err := startDevServer(s.ctx, s.opt, s.history)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		d.Stop()
+		return nil, err
+	}
+	s.lock.Lock()
+	s.current = d
+	s.lock.Unlock()
+	s.target.Store(u)
+	return d, nil
+}
+
+// devServerFailure is the information about the dev server's failure.
+type devServerFailure struct {
+	Err        error
+	Restarting bool // false if supervisor gave up restarting
+}
+
+// Failure returns the last failure. It returns nil if dev server is running or starting first time.
+func (s *supervisor) Failure() *devServerFailure {
+	return s.failure.Load()
+}
+
+// History returns the last lines of dev server's output.
+func (s *supervisor) History() []string {
+	return s.history.Lines()
+}
+
+// Target returns the current URL of dev server. It returns nil while dev server is not ready.
+func (s *supervisor) Target() *url.URL {
+	return s.target.Load()
+}
+
+// watch runs dev server and restarts it with exponential backoff when it exits or fails to start.
+func (s *supervisor) watch() {
+	defer close(s.done)
+	maxRestarts := s.opt.DevServerMaxRestarts
+	if maxRestarts == 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+	restarts := 0
+	backoff := minRestartBackoff
+	for {
+		startedAt := time.Now()
+		d, err := s.start()
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			s.failure.Store(nil)
+			logEvent(s.opt, slog.LevelInfo, "dev server is ready", "url", s.Target().String())
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-d.exited:
+			}
+			s.target.Store(nil)
+			if s.ctx.Err() != nil {
+				return
+			}
+			err = d.err
+			if err == nil {
+				err = ErrDevServerExited
+			}
+			if time.Since(startedAt) > restartResetAfter {
+				restarts = 0
+				backoff = minRestartBackoff
+			}
+		}
+		if maxRestarts < 0 || restarts >= maxRestarts {
+			s.failure.Store(&devServerFailure{Err: err})
+			logEvent(s.opt, slog.LevelError, "dev server stopped. gave up restarting", "restarts", restarts, "error", err)
+			return
+		}
+		restarts++
+		s.failure.Store(&devServerFailure{Err: err, Restarting: true})
+		logEvent(s.opt, slog.LevelWarn, "dev server stopped. restarting", "restarts", restarts, "backoff", backoff, "error", err)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// Stop stops dev server and supervising.
+func (s *supervisor) Stop() {
+	s.cancel()
+	<-s.done
+	s.lock.Lock()
+	d := s.current
+	s.lock.Unlock()
+	if d != nil {
+		d.Stop()
+	}
+}
@@ -0,0 +1,52 @@
+package frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_supervisor_Restart(t *testing.T) {
+	s := startSupervisor(context.Background(), &Opt{
+		FrontEndFolderPath: t.TempDir(),
+		// prints different port for each run, and crashes soon
+		DevServerCommand:     `sh -c "n=$(cat count 2>/dev/null || echo 0); n=$((n+1)); echo $n > count; echo http://localhost:500$n; sleep 0.2"`,
+		DevServerMaxRestarts: 1,
+	})
+	assert.Eventually(t, func() bool {
+		return s.Target() != nil && s.Target().String() == "http://localhost:5001"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return s.Target() != nil && s.Target().String() == "http://localhost:5002"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// gives up after max restarts
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Error("supervisor should give up restarting")
+	}
+	s.Stop()
+}
+
+func Test_supervisor_StopWaitsExit(t *testing.T) {
+	s := startSupervisor(context.Background(), &Opt{
+		FrontEndFolderPath: t.TempDir(),
+		// takes 1 second to exit after SIGTERM
+		DevServerCommand: `sh -c "trap 'sleep 1; exit 0' TERM; echo http://localhost:5173; while true; do sleep 0.1; done"`,
+	})
+	assert.Eventually(t, func() bool {
+		return s.Target() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	s.lock.Lock()
+	process := s.current.process
+	s.lock.Unlock()
+
+	start := time.Now()
+	s.Stop()
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	assert.False(t, processGroupAlive(process))
+}